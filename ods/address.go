@@ -0,0 +1,205 @@
+package ods
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is a rectangular region of a Table, as returned by Table.Range,
+// Table.MergedRanges and Cell.Merge. Rows and columns are 0-based and
+// both ends are inclusive.
+type Range struct {
+	StartRow, StartCol int
+	EndRow, EndCol     int
+}
+
+// ColLettersToIndex converts a spreadsheet column reference such as "A",
+// "B" or "AA" into a 0-based column index. It is case-insensitive and
+// returns -1 if s isn't a valid column reference.
+func ColLettersToIndex(s string) int {
+	if s == "" {
+		return -1
+	}
+	idx := 0
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			r -= 'a' - 'A'
+		case r >= 'A' && r <= 'Z':
+		default:
+			return -1
+		}
+		idx = idx*26 + int(r-'A'+1)
+	}
+	return idx - 1
+}
+
+// IndexToColLetters converts a 0-based column index into a spreadsheet
+// column reference, e.g. 0 -> "A", 26 -> "AA".
+func IndexToColLetters(i int) string {
+	if i < 0 {
+		return ""
+	}
+	var b []byte
+	for i >= 0 {
+		b = append([]byte{byte('A' + i%26)}, b...)
+		i = i/26 - 1
+	}
+	return string(b)
+}
+
+// parseA1 splits an A1-style cell reference such as "B4" into its
+// 0-based row and column indices.
+func parseA1(s string) (row, col int, err error) {
+	i := 0
+	for i < len(s) && isColLetter(s[i]) {
+		i++
+	}
+	if i == 0 || i == len(s) {
+		return 0, 0, fmt.Errorf("ods: invalid cell reference %q", s)
+	}
+	col = ColLettersToIndex(s[:i])
+	n, err := strconv.Atoi(s[i:])
+	if err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("ods: invalid cell reference %q", s)
+	}
+	return n - 1, col, nil
+}
+
+// normalizeRange reorders a range's corners so sr/sc is the top-left and
+// er/ec the bottom-right, the way real ODS producers and the OpenFormula
+// spec treat a range given bottom-right-to-top-left (e.g. "C3:A1").
+func normalizeRange(sr, sc, er, ec int) (int, int, int, int) {
+	if er < sr {
+		sr, er = er, sr
+	}
+	if ec < sc {
+		sc, ec = ec, sc
+	}
+	return sr, sc, er, ec
+}
+
+func isColLetter(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')
+}
+
+// buildIndex materializes the table's (row, col) grid and its merged
+// ranges on first use. ODS repeats columns/rows via
+// number-columns-repeated/number-rows-repeated and marks cells covered
+// by a span as covered-table-cell, so Rows already yields one Cell per
+// grid position; buildIndex just lays that slice of rows out as a
+// lookup-friendly [][]Cell and records spans.
+func (t *Table) buildIndex() {
+	if t.indexed {
+		return
+	}
+	t.indexed = true
+
+	rows := t.Rows(t.styles, t.numberStyles...)
+	t.grid = make([][]Cell, len(rows))
+	for r := range rows {
+		t.grid[r] = rows[r].Cell
+		for c := range t.grid[r] {
+			t.grid[r][c].Row = r
+			t.grid[r][c].Col = c
+			t.grid[r][c].doc = t.doc
+			t.grid[r][c].sheet = t.Name
+		}
+	}
+	t.buildMerges()
+}
+
+// cellAt looks a cell up by its 0-based grid coordinates, building the
+// index on first use. It's the coordinate-based counterpart to Cell,
+// used internally and by the formula resolver.
+func (t *Table) cellAt(row, col int) (Cell, bool) {
+	t.buildIndex()
+	if row < 0 || row >= len(t.grid) || col < 0 || col >= len(t.grid[row]) {
+		return Cell{}, false
+	}
+	return t.grid[row][col], true
+}
+
+func (t *Table) buildMerges() {
+	for r := range t.grid {
+		for c := range t.grid[r] {
+			cell := &t.grid[r][c]
+			if cell.ColSpan <= 1 && cell.RowSpan <= 1 {
+				continue
+			}
+			rowSpan, colSpan := cell.RowSpan, cell.ColSpan
+			if rowSpan < 1 {
+				rowSpan = 1
+			}
+			if colSpan < 1 {
+				colSpan = 1
+			}
+			rg := Range{StartRow: r, StartCol: c, EndRow: r + rowSpan - 1, EndCol: c + colSpan - 1}
+			t.merges = append(t.merges, rg)
+			for rr := rg.StartRow; rr <= rg.EndRow && rr < len(t.grid); rr++ {
+				for cc := rg.StartCol; cc <= rg.EndCol && cc < len(t.grid[rr]); cc++ {
+					t.grid[rr][cc].merge = rg
+					t.grid[rr][cc].merged = true
+				}
+			}
+		}
+	}
+}
+
+// Merge returns the merged range this cell belongs to, whether it is
+// the anchor (top-left) cell or one of the cells it covers, and whether
+// the cell is merged at all.
+func (c *Cell) Merge() (Range, bool) {
+	return c.merge, c.merged
+}
+
+// Cell looks up a single cell by its A1-style reference, e.g. "B4". ok
+// is false if a1 isn't a valid reference or falls outside the table.
+func (t *Table) Cell(a1 string) (cell Cell, ok bool) {
+	row, col, err := parseA1(a1)
+	if err != nil {
+		return Cell{}, false
+	}
+	return t.cellAt(row, col)
+}
+
+// Range returns the cells within an A1-style range such as "A1:C10", as
+// rows of cells. Positions outside the table's populated grid are
+// returned as zero-value Cells carrying just their Row/Col coordinates.
+func (t *Table) Range(a1Range string) ([][]Cell, error) {
+	start, end, ok := strings.Cut(a1Range, ":")
+	if !ok {
+		return nil, fmt.Errorf("ods: invalid range %q", a1Range)
+	}
+	sr, sc, err := parseA1(start)
+	if err != nil {
+		return nil, fmt.Errorf("ods: invalid range %q: %w", a1Range, err)
+	}
+	er, ec, err := parseA1(end)
+	if err != nil {
+		return nil, fmt.Errorf("ods: invalid range %q: %w", a1Range, err)
+	}
+	sr, sc, er, ec = normalizeRange(sr, sc, er, ec)
+
+	t.buildIndex()
+	rows := make([][]Cell, 0, er-sr+1)
+	for r := sr; r <= er; r++ {
+		row := make([]Cell, 0, ec-sc+1)
+		for c := sc; c <= ec; c++ {
+			if r < len(t.grid) && c < len(t.grid[r]) {
+				row = append(row, t.grid[r][c])
+			} else {
+				row = append(row, Cell{Row: r, Col: c})
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// MergedRanges returns every merged region in the table, in grid order.
+func (t *Table) MergedRanges() []Range {
+	t.buildIndex()
+	return t.merges
+}
@@ -0,0 +1,116 @@
+package ods
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestColLettersToIndex(t *testing.T) {
+	cases := map[string]int{"A": 0, "Z": 25, "AA": 26, "AB": 27, "": -1, "1A": -1}
+	for in, want := range cases {
+		if got := ColLettersToIndex(in); got != want {
+			t.Errorf("ColLettersToIndex(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestIndexToColLetters(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB"}
+	for in, want := range cases {
+		if got := IndexToColLetters(in); got != want {
+			t.Errorf("IndexToColLetters(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseA1(t *testing.T) {
+	row, col, err := parseA1("B4")
+	if err != nil || row != 3 || col != 1 {
+		t.Errorf("parseA1(B4) = %d, %d, %v, want 3, 1, nil", row, col, err)
+	}
+	if _, _, err := parseA1("4B"); err == nil {
+		t.Error("parseA1(4B) expected error")
+	}
+}
+
+// TestTableRangeReversed checks that a range given bottom-right to
+// top-left (as real ODS producers and the OpenFormula spec permit) is
+// normalized rather than rejected or panicking on a negative capacity.
+func TestTableRangeReversed(t *testing.T) {
+	var tbl Table
+	reversed, err := tbl.Range("C3:A1")
+	if err != nil {
+		t.Fatalf("Range(C3:A1): %v", err)
+	}
+	forward, err := tbl.Range("A1:C3")
+	if err != nil {
+		t.Fatalf("Range(A1:C3): %v", err)
+	}
+	if len(reversed) != len(forward) || len(reversed[0]) != len(forward[0]) {
+		t.Errorf("Range(C3:A1) shape = %dx%d, want %dx%d matching Range(A1:C3)",
+			len(reversed), len(reversed[0]), len(forward), len(forward[0]))
+	}
+}
+
+// TestTableAddressingAgainstTestODS drives Table.Cell, Table.Range and
+// Table.MergedRanges against test.ods, which has a column spanning two
+// columns (row 6, "cell spanning two columns"), a cell spanning two
+// rows (rows 9-10, "cell spanning two rows") and columns declared with
+// number-columns-repeated.
+func TestTableAddressingAgainstTestODS(t *testing.T) {
+	var doc Doc
+
+	f, err := Open("./test.ods")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer f.Close()
+	if err := f.ParseContent(&doc); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	tbl := doc.Table[0]
+
+	cell, ok := tbl.Cell("A9")
+	if !ok || cell.Value != "aaa" {
+		t.Errorf("Cell(A9) = %+v, %v, want Value %q", cell, ok, "aaa")
+	}
+	cell, ok = tbl.Cell("B9")
+	if !ok || cell.Value != "cell spanning two rows" {
+		t.Errorf("Cell(B9) = %+v, %v, want Value %q", cell, ok, "cell spanning two rows")
+	}
+
+	rng, err := tbl.Range("A9:C10")
+	if err != nil {
+		t.Fatalf("Range(A9:C10): %v", err)
+	}
+	if len(rng) != 2 || len(rng[0]) != 3 {
+		t.Fatalf("Range(A9:C10) shape = %d rows x %d cols, want 2x3", len(rng), len(rng[0]))
+	}
+	if rng[0][0].Value != "aaa" || rng[0][2].Value != "ccc" {
+		t.Errorf("Range(A9:C10) row 0 = %+v", rng[0])
+	}
+	if rng[1][0].Value != "aa" || rng[1][2].Value != "cc" {
+		t.Errorf("Range(A9:C10) row 1 = %+v", rng[1])
+	}
+
+	merges := tbl.MergedRanges()
+	want := []Range{
+		{StartRow: 5, StartCol: 0, EndRow: 5, EndCol: 1},
+		{StartRow: 8, StartCol: 1, EndRow: 9, EndCol: 1},
+	}
+	if len(merges) != len(want) {
+		t.Fatalf("MergedRanges() = %v, want %v", merges, want)
+	}
+	for i, w := range want {
+		if merges[i] != w {
+			t.Errorf("MergedRanges()[%d] = %v, want %v", i, merges[i], w)
+		}
+	}
+
+	if m, merged := rng[0][1].Merge(); !merged || m != want[1] {
+		t.Errorf("Cell(B9).Merge() = %v, %v, want %v, true", m, merged, want[1])
+	}
+}
@@ -0,0 +1,306 @@
+package ods
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewDoc returns an empty spreadsheet document, ready for Doc.AddTable
+// and File.SaveAs/Save. It is the write-side counterpart to parsing an
+// existing file with Open/ParseContent.
+func NewDoc() *Doc {
+	return &Doc{XMLName: xml.Name{Local: "document-content"}}
+}
+
+// AddTable appends a new, empty table (sheet) named name and returns a
+// pointer to it. d.Table holds *Table, so the returned pointer stays
+// valid - and keeps reflecting further edits - across any number of
+// later AddTable calls.
+func (d *Doc) AddTable(name string) *Table {
+	t := &Table{Name: name, doc: d}
+	d.Table = append(d.Table, t)
+	return t
+}
+
+// CellOption customizes a cell set via Table.SetCell.
+type CellOption func(*cellOptions)
+
+type cellOptions struct {
+	style *Style
+}
+
+// WithStyle attaches sty to the cell being set. Identical styles (by
+// content, not by Go value identity) are written out once and shared,
+// the same way ODS itself dedupes automatic styles.
+func WithStyle(sty Style) CellOption {
+	return func(o *cellOptions) { o.style = &sty }
+}
+
+// SetCell sets the cell at addr (an A1-style reference, e.g. "B4") to
+// v, which must be a string, bool, time.Time, or any numeric Go type.
+// It returns the resulting Cell, which can be passed straight to
+// Cell.SetStyle.
+func (t *Table) SetCell(addr string, v interface{}, opts ...CellOption) (Cell, error) {
+	row, col, err := parseA1(addr)
+	if err != nil {
+		return Cell{}, err
+	}
+	if t.doc == nil {
+		return Cell{}, errors.New("ods: table must be created via Doc.AddTable before SetCell")
+	}
+
+	t.ensureRow(row)
+	t.ensureCellsInRow(row, col)
+	tc := &t.XMLRow[row].Cell[col]
+	setCellValue(tc, v)
+
+	var o cellOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.style != nil {
+		tc.StyleName = t.doc.internStyle(*o.style, "table-cell")
+	}
+
+	t.invalidate()
+	cell, ok := t.cellAt(row, col)
+	if !ok {
+		return Cell{}, fmt.Errorf("ods: internal error locating %q after SetCell", addr)
+	}
+	return cell, nil
+}
+
+// MergeRange merges the cells within an A1-style range such as "A1:C2".
+// The top-left cell becomes the anchor, carrying the combined content;
+// the rest of the range is written out as covered-table-cell entries,
+// matching how ODS itself represents merges.
+func (t *Table) MergeRange(a1Range string) error {
+	start, end, ok := strings.Cut(a1Range, ":")
+	if !ok {
+		return fmt.Errorf("ods: invalid range %q", a1Range)
+	}
+	sr, sc, err := parseA1(start)
+	if err != nil {
+		return fmt.Errorf("ods: invalid range %q: %w", a1Range, err)
+	}
+	er, ec, err := parseA1(end)
+	if err != nil {
+		return fmt.Errorf("ods: invalid range %q: %w", a1Range, err)
+	}
+	sr, sc, er, ec = normalizeRange(sr, sc, er, ec)
+
+	for r := sr; r <= er; r++ {
+		t.ensureRow(r)
+		t.ensureCellsInRow(r, ec)
+	}
+
+	anchor := &t.XMLRow[sr].Cell[sc]
+	anchor.ColSpan = ec - sc + 1
+	anchor.RowSpan = er - sr + 1
+
+	for r := sr; r <= er; r++ {
+		for c := sc; c <= ec; c++ {
+			if r == sr && c == sc {
+				continue
+			}
+			t.XMLRow[r].Cell[c] = TCell{XMLName: xml.Name{Local: "covered-table-cell"}}
+		}
+	}
+
+	t.invalidate()
+	return nil
+}
+
+// SetColumnWidth sets the width, in millimeters, of the 0-based column
+// col.
+func (t *Table) SetColumnWidth(col int, mm float64) error {
+	if t.doc == nil {
+		return errors.New("ods: table must be created via Doc.AddTable before SetColumnWidth")
+	}
+	t.ensureColumn(col)
+	sty := Style{ColumnProps: SCol{Width: mmToCm(mm)}}
+	t.XMLColumn[col].StyleName = t.doc.internStyle(sty, "table-column")
+	t.invalidate()
+	return nil
+}
+
+// SetRowHeight sets the height, in millimeters, of the 0-based row row.
+func (t *Table) SetRowHeight(row int, mm float64) error {
+	if t.doc == nil {
+		return errors.New("ods: table must be created via Doc.AddTable before SetRowHeight")
+	}
+	t.ensureRow(row)
+	sty := Style{RowProps: SRow{Height: mmToCm(mm)}}
+	t.XMLRow[row].StyleName = t.doc.internStyle(sty, "table-row")
+	t.invalidate()
+	return nil
+}
+
+// SetStyle assigns sty as this cell's style, to be written out (and
+// deduplicated against identical styles) the next time the owning
+// document is saved. c must have been obtained via Table.Cell,
+// Table.Range, Table.SetCell or Table.MergedRanges, which stamp the
+// back-reference to their table.
+func (c *Cell) SetStyle(sty Style) error {
+	if c.doc == nil {
+		return errors.New("ods: cell has no attached document; obtain it via Table.Cell or Table.SetCell")
+	}
+	t := c.doc.tableByName(c.sheet)
+	if t == nil {
+		return fmt.Errorf("ods: unknown sheet %q", c.sheet)
+	}
+	t.ensureRow(c.Row)
+	t.ensureCellsInRow(c.Row, c.Col)
+	t.XMLRow[c.Row].Cell[c.Col].StyleName = t.doc.internStyle(sty, "table-cell")
+	t.invalidate()
+	return nil
+}
+
+// invalidate drops the cached grid/merge index after a mutation, so the
+// next A1-addressing call rebuilds it from the updated XMLRow/XMLColumn.
+func (t *Table) invalidate() {
+	t.indexed = false
+	t.grid = nil
+	t.merges = nil
+}
+
+func (t *Table) ensureRow(row int) {
+	for len(t.XMLRow) <= row {
+		t.XMLRow = append(t.XMLRow, TRow{})
+	}
+}
+
+func (t *Table) ensureColumn(col int) {
+	for len(t.XMLColumn) <= col {
+		t.XMLColumn = append(t.XMLColumn, TColumn{})
+	}
+}
+
+func (t *Table) ensureCellsInRow(row, col int) {
+	t.ensureRow(row)
+	t.ensureColumn(col)
+	r := &t.XMLRow[row]
+	for len(r.Cell) <= col {
+		r.Cell = append(r.Cell, TCell{XMLName: xml.Name{Local: "table-cell"}})
+	}
+}
+
+// setCellValue fills in a TCell's value-type/value attributes and
+// display text for v, clearing whatever value attribute a previous
+// type left behind so overwriting a cell's type never leaves stale
+// office:date-value/time-value/boolean-value/currency/value pairs
+// alongside the new office:value-type.
+func setCellValue(tc *TCell, v interface{}) {
+	tc.XMLName = xml.Name{Local: "table-cell"}
+	tc.Value = ""
+	tc.DateValue = ""
+	tc.TimeValue = ""
+	tc.BooleanValue = ""
+	tc.Currency = ""
+	switch val := v.(type) {
+	case string:
+		tc.ValueType = "string"
+		tc.setText(val)
+	case bool:
+		tc.ValueType = "boolean"
+		tc.BooleanValue = strconv.FormatBool(val)
+		tc.setText(strings.ToUpper(strconv.FormatBool(val)))
+	case time.Time:
+		tc.ValueType = "date"
+		tc.DateValue = val.Format("2006-01-02")
+		tc.setText(val.Format("2006-01-02"))
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		f := toFloat64(val)
+		tc.ValueType = "float"
+		tc.Value = strconv.FormatFloat(f, 'f', -1, 64)
+		tc.setText(tc.Value)
+	default:
+		tc.ValueType = "string"
+		tc.setText(fmt.Sprint(val))
+	}
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int8:
+		return float64(n)
+	case int16:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint:
+		return float64(n)
+	case uint8:
+		return float64(n)
+	case uint16:
+		return float64(n)
+	case uint32:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
+
+// setText stores s as the cell's single text:p paragraph, escaping it
+// the same way xml.Marshal would, since Par.XML holds raw inner XML.
+func (c *TCell) setText(s string) {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	c.P = []Par{{XML: b.String()}}
+}
+
+func mmToCm(mm float64) string {
+	return strconv.FormatFloat(mm/10, 'f', 4, 64) + "cm"
+}
+
+// internStyle returns the style:name of an automatic style matching
+// sty's properties for the given family ("table-cell", "table-column"
+// or "table-row"), adding a new entry - deduplicated by a hash of its
+// content - if none exists yet.
+func (d *Doc) internStyle(sty Style, family string) string {
+	key := family + ":" + styleHash(sty)
+	if d.styleIndex == nil {
+		d.styleIndex = make(map[string]string)
+	}
+	if name, ok := d.styleIndex[key]; ok {
+		return name
+	}
+
+	d.styleSeq++
+	prefix := "ce"
+	switch family {
+	case "table-column":
+		prefix = "co"
+	case "table-row":
+		prefix = "ro"
+	}
+	name := fmt.Sprintf("%s%d", prefix, d.styleSeq)
+
+	sty.Name = name
+	d.Style = append(d.Style, sty)
+	d.styleIndex[key] = name
+	return name
+}
+
+func styleHash(sty Style) string {
+	sty.Name = ""
+	b, _ := json.Marshal(sty)
+	sum := sha1.Sum(b)
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,144 @@
+package ods
+
+import (
+	"fmt"
+
+	"github.com/ernierasta/odf/ods/formula"
+)
+
+// tableByName finds a table by its table:name attribute.
+func (d *Doc) tableByName(name string) *Table {
+	for _, t := range d.Table {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// docResolver adapts Doc to formula.Resolver, resolving sheet-qualified
+// references against the document's parsed tables.
+type docResolver struct {
+	doc *Doc
+}
+
+func (r *docResolver) Cell(sheet string, row, col int) (formula.Value, bool) {
+	t := r.doc.tableByName(sheet)
+	if t == nil {
+		return formula.Value{}, false
+	}
+	cell, ok := t.cellAt(row, col)
+	if !ok {
+		return formula.Value{}, false
+	}
+	v, err := r.doc.evaluateCell(sheet, cell)
+	if err != nil {
+		return formula.NewError(err.Error()), true
+	}
+	return v, true
+}
+
+func (r *docResolver) Range(sheet string, r1, c1, r2, c2 int) ([][]formula.Value, bool) {
+	t := r.doc.tableByName(sheet)
+	if t == nil {
+		return nil, false
+	}
+	r1, c1, r2, c2 = normalizeRange(r1, c1, r2, c2)
+	t.buildIndex()
+
+	rows := make([][]formula.Value, 0, r2-r1+1)
+	for row := r1; row <= r2; row++ {
+		vals := make([]formula.Value, 0, c2-c1+1)
+		for col := c1; col <= c2; col++ {
+			cell, ok := t.cellAt(row, col)
+			if !ok {
+				vals = append(vals, formula.NewNumber(0))
+				continue
+			}
+			v, err := r.doc.evaluateCell(sheet, cell)
+			if err != nil {
+				vals = append(vals, formula.NewError(err.Error()))
+				continue
+			}
+			vals = append(vals, v)
+		}
+		rows = append(rows, vals)
+	}
+	return rows, true
+}
+
+// cellValue converts a cell's cached value (as already decoded by
+// Cell.Float/Time/Bool) into the equivalent formula.Value, for cells
+// with no formula of their own.
+func cellValue(c Cell) formula.Value {
+	switch c.ValueType {
+	case "float", "percentage", "currency":
+		if f, ok := c.Float(); ok {
+			return formula.NewNumber(f)
+		}
+	case "boolean":
+		if b, ok := c.Bool(); ok {
+			return formula.NewBool(b)
+		}
+	case "date", "time":
+		if t, ok := c.Time(); ok {
+			return formula.NewNumber(float64(t.Unix()))
+		}
+	}
+	return formula.NewString(c.Value)
+}
+
+// evaluateCell resolves a cell's value, evaluating its formula (if any)
+// against sheet. Cells currently being evaluated are tracked by
+// (sheet, row, col) so a circular reference (A1 depends on B1 depends
+// on A1) resolves to a #ERR value instead of recursing forever.
+func (d *Doc) evaluateCell(sheet string, cell Cell) (formula.Value, error) {
+	if cell.FormulaExpr == "" {
+		return cellValue(cell), nil
+	}
+	ref := cellRef{sheet: sheet, row: cell.Row, col: cell.Col}
+	if d.evaluating == nil {
+		d.evaluating = make(map[cellRef]bool)
+	}
+	if d.evaluating[ref] {
+		return formula.NewError("circular reference"), nil
+	}
+	d.evaluating[ref] = true
+	defer delete(d.evaluating, ref)
+
+	node, err := formula.Parse(cell.FormulaExpr)
+	if err != nil {
+		return formula.Value{}, err
+	}
+	return formula.Eval(node, &docResolver{doc: d}, sheet)
+}
+
+// Evaluate resolves the value at addr (an A1-style reference, e.g.
+// "B4") on the named sheet, evaluating its formula if it has one.
+func (d *Doc) Evaluate(sheet, addr string) (formula.Value, error) {
+	t := d.tableByName(sheet)
+	if t == nil {
+		return formula.Value{}, fmt.Errorf("ods: unknown sheet %q", sheet)
+	}
+	row, col, err := parseA1(addr)
+	if err != nil {
+		return formula.Value{}, err
+	}
+	cell, ok := t.cellAt(row, col)
+	if !ok {
+		return formula.Value{}, fmt.Errorf("ods: %s!%s is outside the table", sheet, addr)
+	}
+	return d.evaluateCell(sheet, cell)
+}
+
+// Computed returns the cell's resolved value: its cached value for a
+// plain cell, or the result of evaluating table:formula when present.
+// It requires c to have been obtained via Table.Cell, Table.Range or
+// Table.MergedRanges, which stamp the back-reference to their document;
+// cells from Rows/Strings fall back to their cached value.
+func (c *Cell) Computed() (formula.Value, error) {
+	if c.FormulaExpr == "" || c.doc == nil {
+		return cellValue(*c), nil
+	}
+	return c.doc.evaluateCell(c.sheet, *c)
+}
@@ -0,0 +1,334 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type nodeKind int
+
+const (
+	nodeNumber nodeKind = iota
+	nodeString
+	nodeRef
+	nodeRange
+	nodeCall
+	nodeBinary
+	nodeUnary
+)
+
+// Node is one expression (or sub-expression) of a parsed formula.
+type Node struct {
+	Kind nodeKind
+
+	Num float64
+	Str string
+
+	// Sheet, R1, C1 address a single cell (nodeRef) or the start of a
+	// range (nodeRange); R2/C2 is the range's end. Sheet is "" when the
+	// reference didn't name one explicitly, meaning "the sheet the
+	// formula lives on".
+	Sheet          string
+	R1, C1, R2, C2 int
+
+	Func string  // nodeCall
+	Args []*Node // nodeCall
+
+	Op          string // nodeBinary, nodeUnary
+	Left, Right *Node
+}
+
+// Parse parses an OpenFormula expression, such as the value of a
+// table:formula attribute. A leading "of:=" (or a bare "=") is
+// stripped, matching how ODS stores formulas.
+func Parse(expr string) (*Node, error) {
+	expr = strings.TrimPrefix(expr, "of:=")
+	expr = strings.TrimPrefix(expr, "=")
+
+	p := &parser{lex: newLexer(expr)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("formula: unexpected trailing input near %q", p.tok.str)
+	}
+	return node, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) parseComparison() (*Node, error) {
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && isComparisonOp(p.tok.str) {
+		op := p.tok.str
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Kind: nodeBinary, Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseConcat() (*Node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && p.tok.str == "&" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Kind: nodeBinary, Op: "&", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (*Node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.str == "+" || p.tok.str == "-") {
+		op := p.tok.str
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Kind: nodeBinary, Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (*Node, error) {
+	left, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.str == "*" || p.tok.str == "/") {
+		op := p.tok.str
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parsePower()
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Kind: nodeBinary, Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parsePower parses "^", which OpenFormula (like Excel) defines as
+// left-associative with unary minus binding *tighter* than it on both
+// sides: "-2^2" is "(-2)^2" = 4, not "-(2^2)", and "2^3^2" is "(2^3)^2"
+// = 64, not "2^(3^2)". The loop below (rather than recursing into
+// itself for the right-hand operand) is what gives left-associativity.
+func (p *parser) parsePower() (*Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && p.tok.str == "^" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Kind: nodeBinary, Op: "^", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (*Node, error) {
+	if p.tok.kind == tokOp && p.tok.str == "-" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Kind: nodeUnary, Op: "-", Left: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (*Node, error) {
+	switch p.tok.kind {
+	case tokNumber:
+		n := &Node{Kind: nodeNumber, Num: p.tok.num}
+		return n, p.advance()
+	case tokString:
+		n := &Node{Kind: nodeString, Str: p.tok.str}
+		return n, p.advance()
+	case tokRef:
+		n, err := parseRefBody(p.tok.str)
+		if err != nil {
+			return nil, err
+		}
+		return n, p.advance()
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, fmt.Errorf("formula: expected ')'")
+		}
+		return n, p.advance()
+	case tokIdent:
+		return p.parseCall()
+	default:
+		return nil, fmt.Errorf("formula: unexpected token in expression")
+	}
+}
+
+func (p *parser) parseCall() (*Node, error) {
+	name := strings.ToUpper(p.tok.str)
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokLParen {
+		return nil, fmt.Errorf("formula: expected '(' after function name %s", name)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var args []*Node
+	if p.tok.kind != tokRParen {
+		for {
+			arg, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.tok.kind != tokComma {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p.tok.kind != tokRParen {
+		return nil, fmt.Errorf("formula: expected ')' closing call to %s", name)
+	}
+	return &Node{Kind: nodeCall, Func: name, Args: args}, p.advance()
+}
+
+func isComparisonOp(op string) bool {
+	switch op {
+	case "=", "<>", "<", ">", "<=", ">=":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRefBody parses the contents of a "[...]" reference, e.g. ".A1",
+// "Sheet2.A1" or ".B$3:.D5", into a nodeRef or nodeRange.
+func parseRefBody(body string) (*Node, error) {
+	start, end, isRange := strings.Cut(body, ":")
+
+	sheet1, addr1, err := splitSheetAddr(start)
+	if err != nil {
+		return nil, err
+	}
+	r1, c1, err := parseCellAddr(addr1)
+	if err != nil {
+		return nil, err
+	}
+	if !isRange {
+		return &Node{Kind: nodeRef, Sheet: sheet1, R1: r1, C1: c1}, nil
+	}
+
+	sheet2, addr2, err := splitSheetAddr(end)
+	if err != nil {
+		return nil, err
+	}
+	if sheet2 == "" {
+		sheet2 = sheet1
+	}
+	r2, c2, err := parseCellAddr(addr2)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{Kind: nodeRange, Sheet: sheet2, R1: r1, C1: c1, R2: r2, C2: c2}, nil
+}
+
+// splitSheetAddr splits one side of a reference such as "Sheet2.B$3" or
+// ".D5" into its sheet name (empty if not given) and cell address.
+func splitSheetAddr(s string) (sheet, addr string, err error) {
+	s = strings.TrimPrefix(s, ".")
+	if i := strings.LastIndexByte(s, '.'); i >= 0 {
+		return s[:i], s[i+1:], nil
+	}
+	return "", s, nil
+}
+
+// parseCellAddr parses an A1-style address, ignoring "$" absolute
+// markers, into 0-based row/column indices.
+func parseCellAddr(s string) (row, col int, err error) {
+	s = strings.ReplaceAll(s, "$", "")
+	i := 0
+	for i < len(s) && isIdentStart(s[i]) && !isDigit(s[i]) {
+		i++
+	}
+	if i == 0 || i == len(s) {
+		return 0, 0, fmt.Errorf("formula: invalid cell address %q", s)
+	}
+	col = colLettersToIndex(s[:i])
+	n, err := strconv.Atoi(s[i:])
+	if err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("formula: invalid cell address %q", s)
+	}
+	return n - 1, col, nil
+}
+
+func colLettersToIndex(s string) int {
+	idx := 0
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		idx = idx*26 + int(r-'A'+1)
+	}
+	return idx - 1
+}
@@ -0,0 +1,191 @@
+package formula
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Resolver resolves the cell and range references a formula touches.
+// The ods package's Doc implements this against its parsed tables, via
+// the A1-addressing helpers on Table.
+type Resolver interface {
+	// Cell returns the value at (row, col) on sheet, and whether that
+	// position exists.
+	Cell(sheet string, row, col int) (Value, bool)
+	// Range returns the values within [r1,c1]-[r2,c2] (inclusive) on
+	// sheet, as rows of values, and whether the sheet exists.
+	Range(sheet string, r1, c1, r2, c2 int) ([][]Value, bool)
+}
+
+// Eval evaluates a parsed formula against r, resolving unqualified
+// references against sheet (the sheet the formula itself lives on).
+func Eval(n *Node, r Resolver, sheet string) (Value, error) {
+	return evalNode(n, r, sheet)
+}
+
+func evalNode(n *Node, r Resolver, sheet string) (Value, error) {
+	switch n.Kind {
+	case nodeNumber:
+		return NewNumber(n.Num), nil
+	case nodeString:
+		return NewString(n.Str), nil
+	case nodeRef:
+		sh := refSheet(n.Sheet, sheet)
+		v, ok := r.Cell(sh, n.R1, n.C1)
+		if !ok {
+			return Value{}, fmt.Errorf("formula: unresolved reference on sheet %q", sh)
+		}
+		return v, nil
+	case nodeRange:
+		vals, err := evalRangeFlat(n, r, sheet)
+		if err != nil {
+			return Value{}, err
+		}
+		if len(vals) == 0 {
+			return NewNumber(0), nil
+		}
+		return vals[0], nil
+	case nodeUnary:
+		v, err := evalNode(n.Left, r, sheet)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewNumber(-v.AsNumber()), nil
+	case nodeBinary:
+		return evalBinary(n, r, sheet)
+	case nodeCall:
+		return evalCall(n, r, sheet)
+	default:
+		return Value{}, fmt.Errorf("formula: unhandled expression")
+	}
+}
+
+func refSheet(explicit, current string) string {
+	if explicit != "" {
+		return explicit
+	}
+	return current
+}
+
+func evalRangeFlat(n *Node, r Resolver, sheet string) ([]Value, error) {
+	sh := refSheet(n.Sheet, sheet)
+	grid, ok := r.Range(sh, n.R1, n.C1, n.R2, n.C2)
+	if !ok {
+		return nil, fmt.Errorf("formula: unresolved range on sheet %q", sh)
+	}
+	var out []Value
+	for _, row := range grid {
+		out = append(out, row...)
+	}
+	return out, nil
+}
+
+// evalArgValues evaluates one call argument, flattening it to a slice of
+// Values: a range contributes every cell it covers, anything else
+// contributes its single scalar value.
+func evalArgValues(n *Node, r Resolver, sheet string) ([]Value, error) {
+	if n.Kind == nodeRange {
+		return evalRangeFlat(n, r, sheet)
+	}
+	v, err := evalNode(n, r, sheet)
+	if err != nil {
+		return nil, err
+	}
+	return []Value{v}, nil
+}
+
+func evalBinary(n *Node, r Resolver, sheet string) (Value, error) {
+	left, err := evalNode(n.Left, r, sheet)
+	if err != nil {
+		return Value{}, err
+	}
+	right, err := evalNode(n.Right, r, sheet)
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch n.Op {
+	case "+":
+		return NewNumber(left.AsNumber() + right.AsNumber()), nil
+	case "-":
+		return NewNumber(left.AsNumber() - right.AsNumber()), nil
+	case "*":
+		return NewNumber(left.AsNumber() * right.AsNumber()), nil
+	case "/":
+		if right.AsNumber() == 0 {
+			return Value{}, fmt.Errorf("formula: division by zero")
+		}
+		return NewNumber(left.AsNumber() / right.AsNumber()), nil
+	case "^":
+		return NewNumber(math.Pow(left.AsNumber(), right.AsNumber())), nil
+	case "&":
+		return NewString(left.String() + right.String()), nil
+	case "=":
+		return NewBool(valuesEqual(left, right)), nil
+	case "<>":
+		return NewBool(!valuesEqual(left, right)), nil
+	case "<":
+		return NewBool(compareValues(left, right) < 0), nil
+	case ">":
+		return NewBool(compareValues(left, right) > 0), nil
+	case "<=":
+		return NewBool(compareValues(left, right) <= 0), nil
+	case ">=":
+		return NewBool(compareValues(left, right) >= 0), nil
+	default:
+		return Value{}, fmt.Errorf("formula: unknown operator %q", n.Op)
+	}
+}
+
+// valuesEqual implements OpenFormula "=" semantics: text is compared as
+// text, not coerced through AsNumber, so two non-numeric strings (or a
+// number and its textual spelling, e.g. 1 and "1") are never silently
+// treated as equal just because both reduce to 0 or the same float.
+func valuesEqual(left, right Value) bool {
+	if left.Kind == KindString && right.Kind == KindString {
+		return left.Str == right.Str
+	}
+	if left.Kind == KindString || right.Kind == KindString {
+		str, other := left, right
+		if right.Kind == KindString {
+			str, other = right, left
+		}
+		if _, err := strconv.ParseFloat(str.Str, 64); err != nil {
+			return str.String() == other.String()
+		}
+		// str looks numeric but was typed as text; OpenFormula treats
+		// numbers and numeric text as distinct types, so they never
+		// compare equal.
+		return false
+	}
+	return left.AsNumber() == right.AsNumber()
+}
+
+// compareValues implements OpenFormula "<"/">"/"<="/">=" semantics: like
+// valuesEqual, text is compared as text rather than coerced through
+// AsNumber, so e.g. "abc" < "abd" is a lexical comparison instead of
+// both sides silently reducing to 0. Mixed text/number comparisons
+// follow OpenFormula's type-ordering rule that text always sorts after
+// numbers. It returns a negative number, zero, or a positive number as
+// left is less than, equal to, or greater than right.
+func compareValues(left, right Value) int {
+	if left.Kind == KindString && right.Kind == KindString {
+		return strings.Compare(left.Str, right.Str)
+	}
+	if left.Kind == KindString || right.Kind == KindString {
+		if left.Kind == KindString {
+			return 1
+		}
+		return -1
+	}
+	switch ln, rn := left.AsNumber(), right.AsNumber(); {
+	case ln < rn:
+		return -1
+	case ln > rn:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,144 @@
+package formula
+
+import "testing"
+
+// fakeResolver is a minimal Resolver backed by a single sheet's grid of
+// numbers, for exercising Parse/Eval without an ods.Doc.
+type fakeResolver struct {
+	sheet string
+	grid  [][]float64
+}
+
+func (f *fakeResolver) Cell(sheet string, row, col int) (Value, bool) {
+	if sheet != f.sheet || row < 0 || row >= len(f.grid) || col < 0 || col >= len(f.grid[row]) {
+		return Value{}, false
+	}
+	return NewNumber(f.grid[row][col]), true
+}
+
+func (f *fakeResolver) Range(sheet string, r1, c1, r2, c2 int) ([][]Value, bool) {
+	if sheet != f.sheet {
+		return nil, false
+	}
+	rows := make([][]Value, 0, r2-r1+1)
+	for r := r1; r <= r2; r++ {
+		row := make([]Value, 0, c2-c1+1)
+		for c := c1; c <= c2; c++ {
+			v, ok := f.Cell(sheet, r, c)
+			if !ok {
+				v = NewNumber(0)
+			}
+			row = append(row, v)
+		}
+		rows = append(rows, row)
+	}
+	return rows, true
+}
+
+func evalExpr(t *testing.T, expr string, r Resolver) Value {
+	t.Helper()
+	node, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	v, err := Eval(node, r, "Sheet1")
+	if err != nil {
+		t.Fatalf("Eval(%q): %v", expr, err)
+	}
+	return v
+}
+
+func TestEvalArithmetic(t *testing.T) {
+	r := &fakeResolver{sheet: "Sheet1"}
+	cases := map[string]float64{
+		"of:=1+2*3":   7,
+		"of:=(1+2)*3": 9,
+		"of:=2^3":     8,
+		"of:=-4+1":    -3,
+		"of:=-2^2":    4,  // unary minus binds tighter than ^: (-2)^2, not -(2^2)
+		"of:=2^3^2":   64, // ^ is left-associative: (2^3)^2, not 2^(3^2)
+	}
+	for expr, want := range cases {
+		if got := evalExpr(t, expr, r).AsNumber(); got != want {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestEvalReferencesAndRanges(t *testing.T) {
+	r := &fakeResolver{sheet: "Sheet1", grid: [][]float64{{1, 2}, {3, 4}}}
+
+	if got := evalExpr(t, "of:=[.A1]+[.B2]", r).AsNumber(); got != 5 {
+		t.Errorf("[.A1]+[.B2] = %v, want 5", got)
+	}
+	if got := evalExpr(t, "of:=SUM([.A1:.B2])", r).AsNumber(); got != 10 {
+		t.Errorf("SUM([.A1:.B2]) = %v, want 10", got)
+	}
+	if got := evalExpr(t, "of:=AVERAGE([.A1:.B2])", r).AsNumber(); got != 2.5 {
+		t.Errorf("AVERAGE([.A1:.B2]) = %v, want 2.5", got)
+	}
+}
+
+func TestEvalFunctions(t *testing.T) {
+	r := &fakeResolver{sheet: "Sheet1"}
+	cases := map[string]Value{
+		`of:=IF(1<2,"yes","no")`:   NewString("yes"),
+		`of:=CONCATENATE("a","b")`: NewString("ab"),
+		`of:=LEN("hello")`:         NewNumber(5),
+		`of:=LEFT("hello",2)`:      NewString("he"),
+		`of:=RIGHT("hello",2)`:     NewString("lo"),
+		`of:=MID("hello",2,3)`:     NewString("ell"),
+		`of:=ROUND(1.2345,2)`:      NewNumber(1.23),
+	}
+	for expr, want := range cases {
+		got := evalExpr(t, expr, r)
+		if got.String() != want.String() {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestEvalStringEquality(t *testing.T) {
+	r := &fakeResolver{sheet: "Sheet1"}
+	cases := map[string]Value{
+		`of:="a"="b"`:         NewBool(false),
+		`of:="abc"<>"xyz"`:    NewBool(true),
+		`of:=IF("a"="b",1,2)`: NewNumber(2),
+		`of:=1="1"`:           NewBool(false),
+		`of:="yes"="yes"`:     NewBool(true),
+	}
+	for expr, want := range cases {
+		got := evalExpr(t, expr, r)
+		if got.String() != want.String() {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestEvalStringOrdering(t *testing.T) {
+	r := &fakeResolver{sheet: "Sheet1"}
+	cases := map[string]Value{
+		`of:="abc"<"abd"`:  NewBool(true),
+		`of:="abd"<"abc"`:  NewBool(false),
+		`of:="abc">"abd"`:  NewBool(false),
+		`of:="abc"<="abc"`: NewBool(true),
+		`of:=1<2`:          NewBool(true),
+		`of:="a"<1`:        NewBool(false),
+		`of:=1<"a"`:        NewBool(true),
+	}
+	for expr, want := range cases {
+		got := evalExpr(t, expr, r)
+		if got.String() != want.String() {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestGammaInvRoundTrip(t *testing.T) {
+	alpha, beta := 3.0, 2.0
+	p := gammaP(alpha, 10.0/beta)
+	x := gammaInv(p, alpha, beta)
+	if diff := x - 10.0; diff > 1e-4 || diff < -1e-4 {
+		t.Errorf("gammaInv(gammaP(alpha, 10/beta), alpha, beta) = %v, want ~10", x)
+	}
+}
@@ -0,0 +1,154 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokString
+	tokRef
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	str  string // ident name, operator, or raw reference body (without brackets)
+	num  float64
+}
+
+// lexer turns an OpenFormula expression into a flat token stream.
+// References ("[.A1]", "[Sheet2.B$3:.D5]") are lexed whole, as their
+// contents aren't expressions in their own right.
+type lexer struct {
+	s   string
+	pos int
+}
+
+func newLexer(s string) *lexer { return &lexer{s: s} }
+
+func (l *lexer) peekByte() byte {
+	if l.pos >= len(l.s) {
+		return 0
+	}
+	return l.s[l.pos]
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.s) {
+		return token{kind: tokEOF}, nil
+	}
+
+	c := l.s[l.pos]
+	switch {
+	case c == '[':
+		return l.lexRef()
+	case c == '"':
+		return l.lexString()
+	case c >= '0' && c <= '9', c == '.' && l.pos+1 < len(l.s) && isDigit(l.s[l.pos+1]):
+		return l.lexNumber()
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokComma}, nil
+	case strings.ContainsRune("+-*/^&=", rune(c)):
+		l.pos++
+		return token{kind: tokOp, str: string(c)}, nil
+	case c == '<' || c == '>':
+		op := string(c)
+		l.pos++
+		if l.peekByte() == '=' {
+			op += "="
+			l.pos++
+		} else if c == '<' && l.peekByte() == '>' {
+			op += ">"
+			l.pos++
+		}
+		return token{kind: tokOp, str: op}, nil
+	case isIdentStart(c):
+		return l.lexIdent()
+	default:
+		return token{}, fmt.Errorf("formula: unexpected character %q at offset %d", c, l.pos)
+	}
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.s) && (l.s[l.pos] == ' ' || l.s[l.pos] == '\t') {
+		l.pos++
+	}
+}
+
+func (l *lexer) lexRef() (token, error) {
+	start := l.pos
+	l.pos++ // consume '['
+	for l.pos < len(l.s) && l.s[l.pos] != ']' {
+		l.pos++
+	}
+	if l.pos >= len(l.s) {
+		return token{}, fmt.Errorf("formula: unterminated reference starting at offset %d", start)
+	}
+	body := l.s[start+1 : l.pos]
+	l.pos++ // consume ']'
+	return token{kind: tokRef, str: body}, nil
+}
+
+func (l *lexer) lexString() (token, error) {
+	l.pos++ // consume opening quote
+	var b strings.Builder
+	for l.pos < len(l.s) {
+		c := l.s[l.pos]
+		if c == '"' {
+			// OpenFormula escapes a literal quote as "".
+			if l.pos+1 < len(l.s) && l.s[l.pos+1] == '"' {
+				b.WriteByte('"')
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			return token{kind: tokString, str: b.String()}, nil
+		}
+		b.WriteByte(c)
+		l.pos++
+	}
+	return token{}, fmt.Errorf("formula: unterminated string literal")
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	for l.pos < len(l.s) && (isDigit(l.s[l.pos]) || l.s[l.pos] == '.') {
+		l.pos++
+	}
+	s := l.s[start:l.pos]
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return token{}, fmt.Errorf("formula: invalid number %q", s)
+	}
+	return token{kind: tokNumber, num: f}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	for l.pos < len(l.s) && isIdentPart(l.s[l.pos]) {
+		l.pos++
+	}
+	return token{kind: tokIdent, str: l.s[start:l.pos]}, nil
+}
+
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool { return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') }
+func isIdentPart(c byte) bool  { return isIdentStart(c) || isDigit(c) }
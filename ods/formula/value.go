@@ -0,0 +1,89 @@
+// Package formula parses and evaluates OpenFormula expressions, the
+// "of:=..." formulas found in ODS table:formula attributes. It knows
+// nothing about ODS file structure itself: callers implement Resolver
+// to hand cell and range values to the evaluator, which is how the ods
+// package wires Doc.Evaluate and Cell.Computed up to it without an
+// import cycle.
+package formula
+
+import "strconv"
+
+// Kind identifies the dynamic type carried by a Value.
+type Kind int
+
+const (
+	KindNumber Kind = iota
+	KindString
+	KindBool
+	KindError
+)
+
+// Value is the result of evaluating a formula, or one of its
+// sub-expressions.
+type Value struct {
+	Kind Kind
+	Num  float64
+	Str  string
+	Bool bool
+}
+
+func NewNumber(f float64) Value { return Value{Kind: KindNumber, Num: f} }
+func NewString(s string) Value  { return Value{Kind: KindString, Str: s} }
+func NewBool(b bool) Value      { return Value{Kind: KindBool, Bool: b} }
+func NewError(msg string) Value { return Value{Kind: KindError, Str: msg} }
+
+// IsError reports whether evaluation failed, e.g. an unresolved
+// reference or a division by zero.
+func (v Value) IsError() bool { return v.Kind == KindError }
+
+// AsNumber coerces v to a float64 the way OpenFormula operators do:
+// booleans become 0/1, strings are parsed as numbers (0 if they aren't
+// numeric).
+func (v Value) AsNumber() float64 {
+	switch v.Kind {
+	case KindNumber:
+		return v.Num
+	case KindBool:
+		if v.Bool {
+			return 1
+		}
+		return 0
+	case KindString:
+		f, _ := strconv.ParseFloat(v.Str, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// AsBool coerces v to a boolean: numbers are truthy when non-zero,
+// strings when non-empty.
+func (v Value) AsBool() bool {
+	switch v.Kind {
+	case KindBool:
+		return v.Bool
+	case KindNumber:
+		return v.Num != 0
+	case KindString:
+		return v.Str != ""
+	default:
+		return false
+	}
+}
+
+// String renders v the way a spreadsheet cell would display it.
+func (v Value) String() string {
+	switch v.Kind {
+	case KindNumber:
+		return strconv.FormatFloat(v.Num, 'g', -1, 64)
+	case KindBool:
+		if v.Bool {
+			return "TRUE"
+		}
+		return "FALSE"
+	case KindError:
+		return "#ERR: " + v.Str
+	default:
+		return v.Str
+	}
+}
@@ -0,0 +1,58 @@
+package ods
+
+import "testing"
+
+// TestEvaluateCellCircularReference checks that a formula cycle
+// (A1 depends on B1, B1 depends on A1) resolves to an error value
+// instead of recursing until the stack overflows.
+func TestEvaluateCellCircularReference(t *testing.T) {
+	doc := NewDoc()
+	tbl := doc.AddTable("Sheet1")
+
+	if _, err := tbl.SetCell("A1", 0); err != nil {
+		t.Fatalf("SetCell A1: %v", err)
+	}
+	if _, err := tbl.SetCell("B1", 0); err != nil {
+		t.Fatalf("SetCell B1: %v", err)
+	}
+	tbl.XMLRow[0].Cell[0].Formula = "of:=[.B1]"
+	tbl.XMLRow[0].Cell[1].Formula = "of:=[.A1]"
+	tbl.invalidate()
+
+	v, err := doc.Evaluate("Sheet1", "A1")
+	if err != nil {
+		t.Fatalf("Evaluate A1: %v", err)
+	}
+	if !v.IsError() {
+		t.Errorf("Evaluate A1 = %v, want an error value for the circular reference", v)
+	}
+}
+
+// TestEvaluateCellReversedRange checks that a range reference whose end
+// precedes its start (permitted by OpenFormula) is normalized instead
+// of panicking on a negative slice capacity, the same way Table.Range
+// normalizes a reversed A1 range.
+func TestEvaluateCellReversedRange(t *testing.T) {
+	doc := NewDoc()
+	tbl := doc.AddTable("Sheet1")
+
+	if _, err := tbl.SetCell("A1", 1); err != nil {
+		t.Fatalf("SetCell A1: %v", err)
+	}
+	if _, err := tbl.SetCell("C1", 3); err != nil {
+		t.Fatalf("SetCell C1: %v", err)
+	}
+	if _, err := tbl.SetCell("D1", 0); err != nil {
+		t.Fatalf("SetCell D1: %v", err)
+	}
+	tbl.XMLRow[0].Cell[3].Formula = "of:=SUM([.C1:.A1])"
+	tbl.invalidate()
+
+	v, err := doc.Evaluate("Sheet1", "D1")
+	if err != nil {
+		t.Fatalf("Evaluate D1: %v", err)
+	}
+	if got := v.AsNumber(); got != 4 {
+		t.Errorf("Evaluate D1 = %v, want 4 (SUM of A1:C1 normalized)", got)
+	}
+}
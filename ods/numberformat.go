@@ -0,0 +1,231 @@
+package ods
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// NumberStyle is a decoded ODS number/date/time/percentage/currency/
+// boolean style (<number:date-style>, <number:number-style>, ...),
+// reduced to a display format that Cell.Formatted can apply directly.
+//
+// For Kind "date" or "time", Format is a Go reference-time layout
+// (suitable for time.Time.Format). For the other kinds, Format is a
+// pattern understood by formatNumber, where "0" marks a mandatory digit,
+// "#" an optional digit, "," a thousands separator and any other rune is
+// copied verbatim.
+type NumberStyle struct {
+	Name        string
+	Kind        string
+	Format      string
+	CurrencySym string
+}
+
+// kindFromLocal maps a <number:*-style> element's local name to the
+// Kind recorded on NumberStyle.
+func kindFromLocal(local string) string {
+	switch local {
+	case "date-style":
+		return "date"
+	case "time-style":
+		return "time"
+	case "percentage-style":
+		return "percentage"
+	case "currency-style":
+		return "currency"
+	case "boolean-style":
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// UnmarshalXML builds Format by walking the style's children in document
+// order, the same order ODS applications render them in: every child
+// element contributes its own token(s) to the layout, and any text
+// between elements is copied verbatim.
+//
+// A 24-hour <number:hours style="long"/> is provisionally written as
+// "15", but ODS puts <number:am-pm/> after the hours it governs, so we
+// don't know a style is 12-hour until we reach it; hourStart/hourEnd
+// track that pending token's byte range so it can be patched to the Go
+// 12-hour token "3" if an am-pm sibling does turn up.
+func (n *NumberStyle) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	n.Kind = kindFromLocal(start.Name.Local)
+	n.Name = attrVal(start, "name")
+
+	var buf []byte
+	hourStart, hourEnd := -1, -1
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "day":
+				buf = append(buf, dayToken(attrVal(el, "style"))...)
+			case "month":
+				buf = append(buf, monthToken(attrVal(el, "style"), attrVal(el, "textual") == "true")...)
+			case "year":
+				buf = append(buf, yearToken(attrVal(el, "style"))...)
+			case "hours":
+				style := attrVal(el, "style")
+				if style == "long" {
+					hourStart = len(buf)
+					buf = append(buf, "15"...)
+					hourEnd = len(buf)
+				} else {
+					hourStart, hourEnd = -1, -1
+					buf = append(buf, hourToken(style)...)
+				}
+			case "minutes":
+				buf = append(buf, digitsToken(attrVal(el, "style"), "04", "4")...)
+			case "seconds":
+				buf = append(buf, digitsToken(attrVal(el, "style"), "05", "5")...)
+			case "am-pm":
+				if hourStart >= 0 {
+					patched := append(append([]byte{}, buf[:hourStart]...), "3"...)
+					buf = append(patched, buf[hourEnd:]...)
+					hourStart, hourEnd = -1, -1
+				}
+				buf = append(buf, "PM"...)
+			case "number":
+				buf = append(buf, numberToken(el)...)
+			case "text":
+				s, err := textUntilEnd(d, el.Name)
+				if err != nil {
+					return err
+				}
+				buf = append(buf, s...)
+			case "currency-symbol":
+				s, err := textUntilEnd(d, el.Name)
+				if err != nil {
+					return err
+				}
+				n.CurrencySym = s
+				buf = append(buf, s...)
+			case "text-content":
+				buf = append(buf, "0"...)
+			case "boolean":
+				buf = append(buf, "BOOL"...)
+			default:
+				// Includes style:map, the conditional section ODS uses
+				// for e.g. coloring negative numbers red; unsupported,
+				// so those values render with a plain minus sign.
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.CharData:
+			buf = append(buf, el...)
+		case xml.EndElement:
+			if el.Name == start.Name {
+				n.Format = string(buf)
+				return nil
+			}
+		}
+	}
+}
+
+func attrVal(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// textUntilEnd reads character data up to (and consuming) the matching
+// end element, for leaf elements such as <number:text> or
+// <number:currency-symbol> whose content is plain text.
+func textUntilEnd(d *xml.Decoder, name xml.Name) (string, error) {
+	var b strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch el := tok.(type) {
+		case xml.CharData:
+			b.Write(el)
+		case xml.EndElement:
+			if el.Name == name {
+				return b.String(), nil
+			}
+		}
+	}
+}
+
+func digitsToken(style, long, short string) string {
+	if style == "long" {
+		return long
+	}
+	return short
+}
+
+func dayToken(style string) string {
+	return digitsToken(style, "02", "2")
+}
+
+func monthToken(style string, textual bool) string {
+	switch {
+	case textual && style == "long":
+		return "January"
+	case textual:
+		return "Jan"
+	case style == "long":
+		return "01"
+	default:
+		return "1"
+	}
+}
+
+func yearToken(style string) string {
+	return digitsToken(style, "2006", "06")
+}
+
+// hourToken returns the Go layout token for an hours element: "15" for
+// a 24-hour ("long") style, or the 12-hour token "3" for "short" -
+// matching the am-pm clocks "short" is conventionally paired with. A
+// "long" style is patched to "3" after the fact in UnmarshalXML if it
+// turns out to carry an am-pm sibling.
+func hourToken(style string) string {
+	if style == "long" {
+		return "15"
+	}
+	return "3"
+}
+
+// numberToken builds the digit pattern for a <number:number> element,
+// honoring decimal-places, min-integer-digits and grouping.
+func numberToken(el xml.StartElement) string {
+	decimals := 0
+	minInt := 1
+	grouping := false
+	for _, a := range el.Attr {
+		switch a.Name.Local {
+		case "decimal-places":
+			decimals, _ = strconv.Atoi(a.Value)
+		case "min-integer-digits":
+			minInt, _ = strconv.Atoi(a.Value)
+		case "grouping":
+			grouping = a.Value == "true"
+		}
+	}
+	if minInt < 1 {
+		minInt = 1
+	}
+
+	intPart := strings.Repeat("0", minInt)
+	if grouping {
+		intPart = "#,##" + intPart
+	}
+	if decimals == 0 {
+		return intPart
+	}
+	return intPart + "." + strings.Repeat("0", decimals)
+}
@@ -0,0 +1,51 @@
+package ods
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+// TestNumberStyleTwelveHour checks that a time style carrying an
+// <number:am-pm/> child renders its hours with the Go 12-hour token,
+// not the 24-hour "15" token am-pm clocks never pair with.
+func TestNumberStyleTwelveHour(t *testing.T) {
+	data := `<number:time-style xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0" number:name="T1">` +
+		`<number:hours number:style="long"/>` +
+		`<number:text>:</number:text>` +
+		`<number:minutes number:style="long"/>` +
+		`<number:text> </number:text>` +
+		`<number:am-pm/>` +
+		`</number:time-style>`
+
+	var ns NumberStyle
+	if err := xml.Unmarshal([]byte(data), &ns); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if want := "3:04 PM"; ns.Format != want {
+		t.Errorf("Format = %q, want %q", ns.Format, want)
+	}
+
+	tm := time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC)
+	if got, want := tm.Format(ns.Format), "3:04 PM"; got != want {
+		t.Errorf("Format render = %q, want %q", got, want)
+	}
+}
+
+// TestNumberStyleTwentyFourHour checks that a plain 24-hour time style,
+// with no am-pm child, still uses the "15" token.
+func TestNumberStyleTwentyFourHour(t *testing.T) {
+	data := `<number:time-style xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0" number:name="T2">` +
+		`<number:hours number:style="long"/>` +
+		`<number:text>:</number:text>` +
+		`<number:minutes number:style="long"/>` +
+		`</number:time-style>`
+
+	var ns NumberStyle
+	if err := xml.Unmarshal([]byte(data), &ns); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if want := "15:04"; ns.Format != want {
+		t.Errorf("Format = %q, want %q", ns.Format, want)
+	}
+}
@@ -12,18 +12,94 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/knieriem/odf"
 )
 
+// plainTextBufPool holds *bytes.Buffer values reused by
+// TCell.PlainText's callers (Table.Rows, Table.Strings, Stream) across
+// rows, so extracting a row's text allocates O(non-empty cells) rather
+// than one buffer per row.
+var plainTextBufPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getPlainTextBuf() *bytes.Buffer {
+	b := plainTextBufPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+func putPlainTextBuf(b *bytes.Buffer) {
+	plainTextBufPool.Put(b)
+}
+
 type Doc struct {
 	XMLName xml.Name `xml:"document-content"`
-	Table   []Table  `xml:"body>spreadsheet>table"`
+	Table   []*Table `xml:"body>spreadsheet>table"`
 	Style   []Style  `xml:"automatic-styles>style"`
+
+	// Number/date/time/percentage/currency/boolean formats referenced
+	// by Style.DataStyleName. Use NumberStyles to get them merged, or
+	// NumberStyleByName to look one up directly.
+	DateStyle       []NumberStyle `xml:"automatic-styles>date-style"`
+	TimeStyle       []NumberStyle `xml:"automatic-styles>time-style"`
+	NumberStyle     []NumberStyle `xml:"automatic-styles>number-style"`
+	PercentageStyle []NumberStyle `xml:"automatic-styles>percentage-style"`
+	CurrencyStyle   []NumberStyle `xml:"automatic-styles>currency-style"`
+	BooleanStyle    []NumberStyle `xml:"automatic-styles>boolean-style"`
+
+	// styleIndex and styleSeq back internStyle's content-hash
+	// deduplication of automatic styles added while building a
+	// document for writing.
+	styleIndex map[string]string
+	styleSeq   int
+
+	// evaluating tracks the (sheet, row, col) cells whose formula is
+	// currently being resolved, so evaluateCell can detect a circular
+	// reference instead of recursing until the stack overflows.
+	evaluating map[cellRef]bool
+}
+
+// cellRef identifies a cell's position within a sheet, for cycle
+// detection during formula evaluation.
+type cellRef struct {
+	sheet    string
+	row, col int
+}
+
+// NumberStyles returns every number/date/time/percentage/currency/boolean
+// style declared in the document's automatic-styles, in no particular
+// order.
+func (d *Doc) NumberStyles() []NumberStyle {
+	var all []NumberStyle
+	all = append(all, d.DateStyle...)
+	all = append(all, d.TimeStyle...)
+	all = append(all, d.NumberStyle...)
+	all = append(all, d.PercentageStyle...)
+	all = append(all, d.CurrencyStyle...)
+	all = append(all, d.BooleanStyle...)
+	return all
+}
+
+// NumberStyleByName looks up a previously parsed number style by its
+// style:name, as referenced from Style.DataStyleName.
+func (d *Doc) NumberStyleByName(name string) (NumberStyle, bool) {
+	if name == "" {
+		return NumberStyle{}, false
+	}
+	for _, s := range d.NumberStyles() {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return NumberStyle{}, false
 }
 
 type Style struct {
 	Name           string     `xml:"name,attr"`
+	DataStyleName  string     `xml:"data-style-name,attr"`
 	ColumnProps    SCol       `xml:"table-column-properties"`
 	RowProps       SRow       `xml:"table-row-properties"`
 	CellProps      SCell      `xml:"table-cell-properties"`
@@ -71,6 +147,19 @@ type Table struct {
 	Name      string    `xml:"name,attr"`
 	XMLColumn []TColumn `xml:"table-column"`
 	XMLRow    []TRow    `xml:"table-row"`
+
+	// styles, numberStyles and doc are wired up by ParseContent so that
+	// the A1-addressing helpers (Cell, Range, MergedRanges) don't need
+	// the caller to pass them on every lookup, the way Rows does, and
+	// so cells obtained through them can resolve their own formulas via
+	// Cell.Computed.
+	styles       []Style
+	numberStyles []NumberStyle
+	doc          *Doc
+
+	indexed bool
+	grid    [][]Cell
+	merges  []Range
 }
 
 // Row type is processed row, contaning cells.
@@ -97,6 +186,50 @@ type Cell struct {
 	Height float64
 	// Padding in mm
 	Padding float64
+
+	// ValueType is the office:value-type attribute, e.g. "float",
+	// "percentage", "currency", "date", "time", "boolean", "string".
+	ValueType string
+	// RawValue is the office:value attribute, holding the numeric
+	// value for "float", "percentage" and "currency" cells.
+	RawValue string
+	// DateValue is the office:date-value attribute, for "date" cells.
+	DateValue string
+	// TimeValue is the office:time-value attribute, an ISO 8601
+	// duration such as "PT13H30M00S", for "time" cells.
+	TimeValue string
+	// BooleanValue is the office:boolean-value attribute, for
+	// "boolean" cells.
+	BooleanValue string
+	// CurrencyCode is the office:currency attribute, e.g. "EUR", for
+	// "currency" cells.
+	CurrencyCode string
+	// Format is the display format derived from the cell's number
+	// style, ready to be used by Formatted. Empty if the cell has no
+	// associated data style.
+	Format string
+
+	// FormulaExpr is the raw table:formula attribute, e.g.
+	// "of:=SUM([.A1:.A4])". Empty if the cell holds a plain value.
+	FormulaExpr string
+
+	// Row and Col are the cell's 0-based coordinates within its Table.
+	// Only populated by the A1-addressing helpers (Table.Cell,
+	// Table.Range, Table.MergedRanges), zero otherwise.
+	Row, Col int
+	// ColSpan and RowSpan are number-columns-spanned/number-rows-spanned
+	// on a merge's anchor cell, 0 on ordinary cells and on the cells a
+	// merge covers.
+	ColSpan, RowSpan int
+
+	merge  Range
+	merged bool
+
+	// doc and sheet back-reference the owning document and table name,
+	// letting Computed resolve this cell's formula. Only set on cells
+	// obtained through Table.Cell, Table.Range or Table.MergedRanges.
+	doc   *Doc
+	sheet string
 }
 
 type TColumn struct {
@@ -193,7 +326,7 @@ func (r *TRow) Strings(b *bytes.Buffer) (row []string) {
 	return
 }
 
-func (r *TRow) Cells(b *bytes.Buffer, styles []Style, sRow SRow, tColumns []TColumn) Row {
+func (r *TRow) Cells(b *bytes.Buffer, styles []Style, sRow SRow, tColumns []TColumn, numberStyles ...NumberStyle) Row {
 	n := len(r.Cell)
 	if n == 0 {
 		return Row{}
@@ -273,10 +406,26 @@ func (r *TRow) Cells(b *bytes.Buffer, styles []Style, sRow SRow, tColumns []TCol
 			}
 		}
 		sCol := GetColStyleByName(coln.StyleName, styles)
-		sCell := GetCellStyleByName(c.StyleName, styles)
-		sDefaultColCell := GetCellStyleByName(coln.DefaltCellStyle, styles)
-		cell := ConsolidateStyles(sRow, sCol, sCell, sDefaultColCell)
+		sCell := getCellStyleByName(c.StyleName, styles)
+		sDefaultColCell := getCellStyleByName(coln.DefaltCellStyle, styles)
+		cell := consolidateStyles(sRow, sCol, sCell, sDefaultColCell)
 		cell.Value = plain
+		cell.ValueType = c.ValueType
+		cell.RawValue = c.Value
+		cell.DateValue = c.DateValue
+		cell.TimeValue = c.TimeValue
+		cell.BooleanValue = c.BooleanValue
+		cell.CurrencyCode = c.Currency
+		dataStyleName := ""
+		if sCell != nil {
+			dataStyleName = sCell.DataStyleName
+		}
+		if style, ok := GetNumberStyleByName(dataStyleName, numberStyles); ok {
+			cell.Format = style.Format
+		}
+		cell.ColSpan = c.ColSpan
+		cell.RowSpan = c.RowSpan
+		cell.FormulaExpr = c.Formula
 
 		if c.ColSpan != 0 {
 			cell.Width = sum
@@ -300,6 +449,10 @@ type TCell struct {
 	// attributes
 	ValueType    string `xml:"value-type,attr"`
 	Value        string `xml:"value,attr"`
+	DateValue    string `xml:"date-value,attr"`
+	TimeValue    string `xml:"time-value,attr"`
+	BooleanValue string `xml:"boolean-value,attr"`
+	Currency     string `xml:"currency,attr"`
 	Formula      string `xml:"formula,attr"`
 	RepeatedCols int    `xml:"number-columns-repeated,attr"`
 	ColSpan      int    `xml:"number-columns-spanned,attr"`
@@ -432,14 +585,15 @@ func (t *Table) removeTrailingEmptyRows() int {
 }
 
 func (t *Table) Strings() (s [][]string) {
-	var b bytes.Buffer
+	b := getPlainTextBuf()
+	defer putPlainTextBuf(b)
 
 	n := t.removeTrailingEmptyRows()
 
 	s = make([][]string, n)
 	w := 0
 	for _, r := range t.XMLRow {
-		row := r.Strings(&b)
+		row := r.Strings(b)
 		s[w] = row
 		w++
 		for j := 1; j < r.RepeatedRows; j++ {
@@ -466,15 +620,16 @@ func (r *Row) IsEmpty() bool {
 	return true
 }
 
-func (t *Table) Rows(styles []Style) (rr []Row) {
-	var b bytes.Buffer
+func (t *Table) Rows(styles []Style, numberStyles ...NumberStyle) (rr []Row) {
+	b := getPlainTextBuf()
+	defer putPlainTextBuf(b)
 
 	n := t.removeTrailingEmptyRows()
 
 	rr = make([]Row, n)
 	w := 0
 	for _, r := range t.XMLRow {
-		row := r.Cells(&b, styles, GetRowStyleByName(r.StyleName, styles), t.XMLColumn)
+		row := r.Cells(b, styles, GetRowStyleByName(r.StyleName, styles), t.XMLColumn, numberStyles...)
 		rr[w] = row
 		w++
 		for j := 1; j < r.RepeatedRows; j++ {
@@ -503,19 +658,63 @@ func GetColStyleByName(name string, styles []Style) SCol {
 	return SCol{}
 }
 
+// GetCellStyleByName looks up a cell style by its style:name attribute.
+// Returns the zero Style if name isn't found (or is empty).
 func GetCellStyleByName(name string, styles []Style) Style {
+	if s := getCellStyleByName(name, styles); s != nil {
+		return *s
+	}
+	return Style{}
+}
+
+// getCellStyleByName is GetCellStyleByName's internal counterpart: it
+// returns a pointer into styles rather than a copy, since Style embeds
+// SCol which in turn embeds a full Cell - copying it on every cell of
+// every row would be wasteful. Returns nil if name isn't found (or is
+// empty).
+func getCellStyleByName(name string, styles []Style) *Style {
 	for i := range styles {
 		if styles[i].Name == name {
-			return styles[i]
+			return &styles[i]
 		}
 	}
-	return Style{}
+	return nil
+}
+
+// GetNumberStyleByName looks up a number/date/time/percentage/currency/
+// boolean style by its style:name attribute, as referenced by
+// Style.DataStyleName.
+func GetNumberStyleByName(name string, numberStyles []NumberStyle) (NumberStyle, bool) {
+	if name == "" {
+		return NumberStyle{}, false
+	}
+	for i := range numberStyles {
+		if numberStyles[i].Name == name {
+			return numberStyles[i], true
+		}
+	}
+	return NumberStyle{}, false
 }
 
 // ConsolidateStyles - TODO: add all params
 func ConsolidateStyles(r SRow, c SCol, cell, defaultColCell Style) Cell {
+	return consolidateStyles(r, c, &cell, &defaultColCell)
+}
+
+// consolidateStyles is ConsolidateStyles's internal counterpart: cell
+// and defaultColCell are pointers, as returned by getCellStyleByName,
+// rather than copies of (the none too small) Style; either may be nil
+// when no matching style was found.
+func consolidateStyles(r SRow, c SCol, cell, defaultColCell *Style) Cell {
 	var err error
 
+	if cell == nil {
+		cell = &Style{}
+	}
+	if defaultColCell == nil {
+		defaultColCell = &Style{}
+	}
+
 	w, err := ToMM(c.Width)
 	if err != nil {
 		log.Println(err)
@@ -646,6 +845,15 @@ func (f *File) ParseContent(doc *Doc) (err error) {
 	defer content.Close()
 
 	d := xml.NewDecoder(content)
-	err = d.Decode(doc)
+	if err = d.Decode(doc); err != nil {
+		return
+	}
+
+	numberStyles := doc.NumberStyles()
+	for i := range doc.Table {
+		doc.Table[i].styles = doc.Style
+		doc.Table[i].numberStyles = numberStyles
+		doc.Table[i].doc = doc
+	}
 	return
 }
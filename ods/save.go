@@ -0,0 +1,397 @@
+package ods
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const mimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+// Save writes doc out as a complete ODS file (a zip archive containing
+// mimetype, content.xml, styles.xml, meta.xml and
+// META-INF/manifest.xml), mirroring the layout Open/ParseContent read.
+func (d *Doc) Save(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mw, mimeType); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"META-INF/manifest.xml": renderManifest(),
+		"meta.xml":              renderMeta(),
+		"styles.xml":            renderStylesXML(),
+		"content.xml":           renderContentXML(d),
+	}
+	for _, name := range []string{"META-INF/manifest.xml", "meta.xml", "styles.xml", "content.xml"} {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, files[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// SaveAs writes doc out to path as a complete ODS file.
+func (d *Doc) SaveAs(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return d.Save(f)
+}
+
+func renderManifest() string {
+	return `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">` +
+		`<manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="` + mimeType + `"/>` +
+		`<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>` +
+		`<manifest:file-entry manifest:full-path="styles.xml" manifest:media-type="text/xml"/>` +
+		`<manifest:file-entry manifest:full-path="meta.xml" manifest:media-type="text/xml"/>` +
+		`</manifest:manifest>`
+}
+
+func renderMeta() string {
+	return `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" office:version="1.2">` +
+		`<office:meta/></office:document-meta>`
+}
+
+func renderStylesXML() string {
+	return `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" ` +
+		`xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" office:version="1.2">` +
+		`<office:styles/></office:document-styles>`
+}
+
+func renderContentXML(d *Doc) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	b.WriteString(`<office:document-content`)
+	b.WriteString(` xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"`)
+	b.WriteString(` xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"`)
+	b.WriteString(` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"`)
+	b.WriteString(` xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"`)
+	b.WriteString(` xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"`)
+	b.WriteString(` xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"`)
+	b.WriteString(` office:version="1.2">`)
+
+	b.WriteString(`<office:automatic-styles>`)
+	for _, ns := range d.NumberStyles() {
+		writeNumberStyle(&b, ns)
+	}
+	for _, sty := range d.Style {
+		writeStyle(&b, sty)
+	}
+	b.WriteString(`</office:automatic-styles>`)
+
+	b.WriteString(`<office:body><office:spreadsheet>`)
+	for _, t := range d.Table {
+		writeTable(&b, t)
+	}
+	b.WriteString(`</office:spreadsheet></office:body>`)
+
+	b.WriteString(`</office:document-content>`)
+	return b.String()
+}
+
+func writeStyle(b *strings.Builder, sty Style) {
+	family := styleFamily(sty)
+	fmt.Fprintf(b, `<style:style style:name="%s" style:family="%s"`, escapeAttr(sty.Name), family)
+	if sty.DataStyleName != "" {
+		fmt.Fprintf(b, ` style:data-style-name="%s"`, escapeAttr(sty.DataStyleName))
+	}
+	b.WriteString(`>`)
+
+	if sty.ColumnProps != (SCol{}) {
+		fmt.Fprintf(b, `<style:table-column-properties`)
+		writeAttr(b, "style:column-width", sty.ColumnProps.Width)
+		writeAttr(b, "fo:break-before", sty.ColumnProps.BreakBefore)
+		b.WriteString(`/>`)
+	}
+	if sty.RowProps != (SRow{}) {
+		fmt.Fprintf(b, `<style:table-row-properties`)
+		writeAttr(b, "style:row-height", sty.RowProps.Height)
+		writeAttr(b, "fo:break-before", sty.RowProps.BreakBefore)
+		if sty.RowProps.OptimalHeight {
+			b.WriteString(` style:use-optimal-row-height="true"`)
+		}
+		b.WriteString(`/>`)
+	}
+	if hasCellProps(sty.CellProps) {
+		b.WriteString(`<style:table-cell-properties`)
+		writeAttr(b, "fo:border-top", sty.CellProps.BorderTop)
+		writeAttr(b, "fo:border-bottom", sty.CellProps.BorderBottom)
+		writeAttr(b, "fo:border-left", sty.CellProps.BorderLeft)
+		writeAttr(b, "fo:border-right", sty.CellProps.BorderRight)
+		writeAttr(b, "fo:background-color", sty.CellProps.BackgroundColor)
+		writeAttr(b, "style:vertical-align", sty.CellProps.AlignVertical)
+		b.WriteString(`/>`)
+	}
+	if sty.TextProps != (SText{}) {
+		b.WriteString(`<style:text-properties`)
+		writeAttr(b, "style:font-name", sty.TextProps.Name)
+		writeAttr(b, "fo:font-size", sty.TextProps.Size)
+		writeAttr(b, "fo:font-weight", sty.TextProps.Weight)
+		writeAttr(b, "fo:color", sty.TextProps.Color)
+		b.WriteString(`/>`)
+	}
+	if sty.ParagraphProps != (SParagraph{}) {
+		b.WriteString(`<style:paragraph-properties`)
+		writeAttr(b, "fo:text-align", sty.ParagraphProps.Align)
+		writeAttr(b, "fo:margin-left", sty.ParagraphProps.MarginLeft)
+		b.WriteString(`/>`)
+	}
+	b.WriteString(`</style:style>`)
+}
+
+func hasCellProps(c SCell) bool {
+	return c != SCell{}
+}
+
+// numberStyleElem maps a NumberStyle.Kind to the ODS element that
+// declares it.
+func numberStyleElem(kind string) string {
+	switch kind {
+	case "date":
+		return "date-style"
+	case "time":
+		return "time-style"
+	case "percentage":
+		return "percentage-style"
+	case "currency":
+		return "currency-style"
+	case "boolean":
+		return "boolean-style"
+	default:
+		return "number-style"
+	}
+}
+
+// writeNumberStyle serializes ns back to the <number:*-style> element it
+// was decoded from, so a style:data-style-name written by writeStyle
+// keeps resolving to a real style after a round trip through Save.
+func writeNumberStyle(b *strings.Builder, ns NumberStyle) {
+	elem := numberStyleElem(ns.Kind)
+	fmt.Fprintf(b, `<number:%s number:name="%s">`, elem, escapeAttr(ns.Name))
+	switch ns.Kind {
+	case "boolean":
+		b.WriteString(`<number:boolean/>`)
+	case "date", "time":
+		writeDateTimeTokens(b, ns.Format)
+	default:
+		writeNumberTokens(b, ns)
+	}
+	fmt.Fprintf(b, `</number:%s>`, elem)
+}
+
+// dateTimeTokens maps the Go reference-time tokens dayToken, monthToken,
+// yearToken, hourToken and digitsToken can produce back to the ODS
+// elements that produce them, longest token first so e.g. "2006" is
+// matched before the "06" it contains.
+var dateTimeTokens = func() []struct{ tok, elem string } {
+	toks := []struct{ tok, elem string }{
+		{"2006", `<number:year number:style="long"/>`},
+		{"06", `<number:year number:style="short"/>`},
+		{"January", `<number:month number:style="long" number:textual="true"/>`},
+		{"Jan", `<number:month number:style="short" number:textual="true"/>`},
+		{"01", `<number:month number:style="long"/>`},
+		{"1", `<number:month number:style="short"/>`},
+		{"02", `<number:day number:style="long"/>`},
+		{"2", `<number:day number:style="short"/>`},
+		{"15", `<number:hours number:style="long"/>`},
+		{"3", `<number:hours number:style="short"/>`},
+		{"04", `<number:minutes number:style="long"/>`},
+		{"4", `<number:minutes number:style="short"/>`},
+		{"05", `<number:seconds number:style="long"/>`},
+		{"5", `<number:seconds number:style="short"/>`},
+		{"PM", `<number:am-pm/>`},
+	}
+	sort.Slice(toks, func(i, j int) bool { return len(toks[i].tok) > len(toks[j].tok) })
+	return toks
+}()
+
+// writeDateTimeTokens reconstructs the <number:year>/<number:month>/...
+// children of a date or time style from its Go-layout Format string,
+// greedily matching the longest known token at each position and
+// emitting any text in between as <number:text>.
+func writeDateTimeTokens(b *strings.Builder, format string) {
+	i := 0
+	for i < len(format) {
+		tok := matchDateTimeToken(format[i:])
+		if tok != nil {
+			b.WriteString(tok.elem)
+			i += len(tok.tok)
+			continue
+		}
+		j := i + 1
+		for j < len(format) && matchDateTimeToken(format[j:]) == nil {
+			j++
+		}
+		writeNumberText(b, format[i:j])
+		i = j
+	}
+}
+
+func matchDateTimeToken(s string) *struct{ tok, elem string } {
+	for i := range dateTimeTokens {
+		if strings.HasPrefix(s, dateTimeTokens[i].tok) {
+			return &dateTimeTokens[i]
+		}
+	}
+	return nil
+}
+
+// writeNumberTokens reconstructs the <number:number> (plus any literal
+// prefix/suffix, such as a currency symbol or a percent sign) that
+// produced ns.Format, the pattern formatNumber renders numbers with.
+func writeNumberTokens(b *strings.Builder, ns NumberStyle) {
+	pattern := ns.Format
+	start := strings.IndexAny(pattern, "0#")
+	end := strings.LastIndexAny(pattern, "0#")
+	if start < 0 {
+		writeNumberText(b, pattern)
+		return
+	}
+	prefix, digits, suffix := pattern[:start], pattern[start:end+1], pattern[end+1:]
+
+	grouping := strings.HasPrefix(digits, "#,##")
+	rest := strings.TrimPrefix(digits, "#,##")
+	minInt, decimals := len(rest), 0
+	if i := strings.IndexByte(rest, '.'); i >= 0 {
+		minInt, decimals = i, len(rest)-i-1
+	}
+	if minInt < 1 {
+		minInt = 1
+	}
+
+	writeCurrencyOrText(b, prefix, ns)
+	fmt.Fprintf(b, `<number:number number:decimal-places="%d" number:min-integer-digits="%d"`, decimals, minInt)
+	if grouping {
+		b.WriteString(` number:grouping="true"`)
+	}
+	b.WriteString(`/>`)
+	writeCurrencyOrText(b, suffix, ns)
+}
+
+// writeCurrencyOrText writes s as a <number:currency-symbol> if it's
+// exactly the style's currency symbol, so re-parsing recovers
+// NumberStyle.CurrencySym, or as plain <number:text> otherwise.
+func writeCurrencyOrText(b *strings.Builder, s string, ns NumberStyle) {
+	if ns.Kind == "currency" && s != "" && s == ns.CurrencySym {
+		b.WriteString(`<number:currency-symbol>`)
+		xml.EscapeText(b, []byte(s))
+		b.WriteString(`</number:currency-symbol>`)
+		return
+	}
+	writeNumberText(b, s)
+}
+
+func writeNumberText(b *strings.Builder, s string) {
+	if s == "" {
+		return
+	}
+	b.WriteString(`<number:text>`)
+	xml.EscapeText(b, []byte(s))
+	b.WriteString(`</number:text>`)
+}
+
+func styleFamily(sty Style) string {
+	switch {
+	case sty.ColumnProps != (SCol{}):
+		return "table-column"
+	case sty.RowProps != (SRow{}):
+		return "table-row"
+	default:
+		return "table-cell"
+	}
+}
+
+func writeAttr(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, ` %s="%s"`, name, escapeAttr(value))
+}
+
+func writeTable(b *strings.Builder, t *Table) {
+	fmt.Fprintf(b, `<table:table table:name="%s">`, escapeAttr(t.Name))
+	for _, col := range t.XMLColumn {
+		b.WriteString(`<table:table-column`)
+		writeAttr(b, "table:style-name", col.StyleName)
+		writeAttr(b, "table:default-cell-style-name", col.DefaltCellStyle)
+		if col.RepeatedCols > 0 {
+			fmt.Fprintf(b, ` table:number-columns-repeated="%d"`, col.RepeatedCols)
+		}
+		b.WriteString(`/>`)
+	}
+	for _, row := range t.XMLRow {
+		b.WriteString(`<table:table-row`)
+		writeAttr(b, "table:style-name", row.StyleName)
+		if row.RepeatedRows > 0 {
+			fmt.Fprintf(b, ` table:number-rows-repeated="%d"`, row.RepeatedRows)
+		}
+		b.WriteString(`>`)
+		for _, c := range row.Cell {
+			writeCell(b, c)
+		}
+		b.WriteString(`</table:table-row>`)
+	}
+	b.WriteString(`</table:table>`)
+}
+
+func writeCell(b *strings.Builder, c TCell) {
+	local := c.XMLName.Local
+	if local == "" {
+		local = "table-cell"
+	}
+	fmt.Fprintf(b, `<table:%s`, local)
+	writeAttr(b, "office:value-type", c.ValueType)
+	writeAttr(b, "office:value", c.Value)
+	writeAttr(b, "office:date-value", c.DateValue)
+	writeAttr(b, "office:time-value", c.TimeValue)
+	writeAttr(b, "office:boolean-value", c.BooleanValue)
+	writeAttr(b, "office:currency", c.Currency)
+	writeAttr(b, "table:formula", c.Formula)
+	writeAttr(b, "table:style-name", c.StyleName)
+	if c.ColSpan > 1 {
+		fmt.Fprintf(b, ` table:number-columns-spanned="%d"`, c.ColSpan)
+	}
+	if c.RowSpan > 1 {
+		fmt.Fprintf(b, ` table:number-rows-spanned="%d"`, c.RowSpan)
+	}
+	if c.RepeatedCols > 1 {
+		fmt.Fprintf(b, ` table:number-columns-repeated="%d"`, c.RepeatedCols)
+	}
+
+	if len(c.P) == 0 {
+		b.WriteString(`/>`)
+		return
+	}
+	b.WriteString(`>`)
+	for _, p := range c.P {
+		b.WriteString(`<text:p>`)
+		b.WriteString(p.XML)
+		b.WriteString(`</text:p>`)
+	}
+	fmt.Fprintf(b, `</table:%s>`, local)
+}
+
+func escapeAttr(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return r.Replace(s)
+}
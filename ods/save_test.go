@@ -0,0 +1,234 @@
+package ods
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestSaveRoundTrip reads test.ods, writes it back out with Save, re-reads
+// the result and checks that every cell's display value survived the
+// round trip unchanged.
+func TestSaveRoundTrip(t *testing.T) {
+	var doc Doc
+
+	f, err := Open("./test.ods")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer f.Close()
+	if err := f.ParseContent(&doc); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	f2, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	defer f2.Close()
+
+	var doc2 Doc
+	if err := f2.ParseContent(&doc2); err != nil {
+		t.Fatalf("ParseContent: %v", err)
+	}
+
+	if len(doc2.Table) != len(doc.Table) {
+		t.Fatalf("got %d tables, want %d", len(doc2.Table), len(doc.Table))
+	}
+	for i := range doc.Table {
+		want := doc.Table[i].Rows(doc.Style, doc.NumberStyles()...)
+		got := doc2.Table[i].Rows(doc2.Style, doc2.NumberStyles()...)
+		if len(got) != len(want) {
+			t.Errorf("table %d: got %d rows, want %d", i, len(got), len(want))
+			continue
+		}
+		for r := range want {
+			if len(got[r].Cell) != len(want[r].Cell) {
+				t.Errorf("table %d row %d: got %d cells, want %d", i, r, len(got[r].Cell), len(want[r].Cell))
+				continue
+			}
+			for c := range want[r].Cell {
+				if got[r].Cell[c].Value != want[r].Cell[c].Value {
+					t.Errorf("table %d row %d cell %d: got %q, want %q", i, r, c, got[r].Cell[c].Value, want[r].Cell[c].Value)
+				}
+			}
+		}
+	}
+}
+
+// TestSaveBuiltDoc exercises the write path end to end: build a document
+// from scratch, save it, and check that values, a merge and a shared
+// style all survive being reopened.
+func TestSaveBuiltDoc(t *testing.T) {
+	doc := NewDoc()
+	tbl := doc.AddTable("Sheet1")
+
+	sty := Style{CellProps: SCell{BackgroundColor: "#ff0000"}}
+	if _, err := tbl.SetCell("A1", "red", WithStyle(sty)); err != nil {
+		t.Fatalf("SetCell A1: %v", err)
+	}
+	cell, err := tbl.SetCell("A2", "also red")
+	if err != nil {
+		t.Fatalf("SetCell A2: %v", err)
+	}
+	if err := cell.SetStyle(sty); err != nil {
+		t.Fatalf("SetStyle: %v", err)
+	}
+	if _, err := tbl.SetCell("B1", 42.5); err != nil {
+		t.Fatalf("SetCell B1: %v", err)
+	}
+	if _, err := tbl.SetCell("C1", "wide"); err != nil {
+		t.Fatalf("SetCell C1: %v", err)
+	}
+	if err := tbl.MergeRange("C1:D1"); err != nil {
+		t.Fatalf("MergeRange: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	f, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	defer f.Close()
+
+	var got Doc
+	if err := f.ParseContent(&got); err != nil {
+		t.Fatalf("ParseContent: %v", err)
+	}
+
+	cellCount := 0
+	for _, sty := range got.Style {
+		if sty.CellProps.BackgroundColor == "#ff0000" {
+			cellCount++
+		}
+	}
+	if cellCount != 1 {
+		t.Errorf("got %d distinct red cell styles, want 1 (deduplicated)", cellCount)
+	}
+
+	t0 := got.Table[0]
+	rows := t0.Rows(got.Style, got.NumberStyles()...)
+	if len(rows) < 1 || len(rows[0].Cell) < 3 {
+		t.Fatalf("unexpected shape: %d rows, first row has %d cells", len(rows), len(rows[0].Cell))
+	}
+	if got := rows[0].Cell[0].Value; got != "red" {
+		t.Errorf("A1 = %q, want %q", got, "red")
+	}
+	if got := rows[0].Cell[1].Value; got != "42.5" {
+		t.Errorf("B1 = %q, want %q", got, "42.5")
+	}
+	mr := t0.MergedRanges()
+	if len(mr) != 1 || mr[0].StartCol != 2 || mr[0].EndCol != 3 {
+		t.Errorf("MergedRanges() = %v, want one range covering C1:D1", mr)
+	}
+}
+
+// TestAddTableHandleSurvivesLaterAddTable guards against a stale-pointer
+// regression: a *Table returned by AddTable must stay valid - and keep
+// writing through to the saved document - even after further sheets are
+// added to the same Doc.
+func TestAddTableHandleSurvivesLaterAddTable(t *testing.T) {
+	doc := NewDoc()
+	tbl1 := doc.AddTable("Sheet1")
+	if _, err := tbl1.SetCell("A1", "first"); err != nil {
+		t.Fatalf("SetCell A1: %v", err)
+	}
+
+	doc.AddTable("Sheet2")
+
+	if _, err := tbl1.SetCell("A2", "second"); err != nil {
+		t.Fatalf("SetCell A2: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	f, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	defer f.Close()
+
+	var got Doc
+	if err := f.ParseContent(&got); err != nil {
+		t.Fatalf("ParseContent: %v", err)
+	}
+
+	rows := got.Table[0].Rows(got.Style, got.NumberStyles()...)
+	if len(rows) < 2 || len(rows[1].Cell) < 1 {
+		t.Fatalf("unexpected shape: %d rows", len(rows))
+	}
+	if got := rows[1].Cell[0].Value; got != "second" {
+		t.Errorf("A2 = %q, want %q", got, "second")
+	}
+}
+
+// TestSetCellClearsPreviousType guards against a stale-attribute
+// regression: overwriting a cell with a value of a different type must
+// clear whatever office:*-value attribute the previous type left
+// behind, not just update office:value-type.
+func TestSetCellClearsPreviousType(t *testing.T) {
+	doc := NewDoc()
+	tbl := doc.AddTable("Sheet1")
+
+	if _, err := tbl.SetCell("A1", true); err != nil {
+		t.Fatalf("SetCell A1 (bool): %v", err)
+	}
+	if _, err := tbl.SetCell("A1", "hello"); err != nil {
+		t.Fatalf("SetCell A1 (string): %v", err)
+	}
+
+	tc := tbl.XMLRow[0].Cell[0]
+	if tc.ValueType != "string" {
+		t.Errorf("ValueType = %q, want %q", tc.ValueType, "string")
+	}
+	if tc.BooleanValue != "" {
+		t.Errorf("stale BooleanValue = %q, want empty", tc.BooleanValue)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if strings.Contains(buf.String(), "boolean-value") {
+		t.Errorf("saved content.xml still contains boolean-value: %s", buf.String())
+	}
+}
+
+// TestMergeRangeReversed checks that a range given bottom-right to
+// top-left is normalized, the way real ODS producers and the
+// OpenFormula spec treat it, rather than rejected or panicking.
+func TestMergeRangeReversed(t *testing.T) {
+	doc := NewDoc()
+	tbl := doc.AddTable("Sheet1")
+
+	if _, err := tbl.SetCell("C1", "wide"); err != nil {
+		t.Fatalf("SetCell C1: %v", err)
+	}
+	if err := tbl.MergeRange("D1:C1"); err != nil {
+		t.Fatalf("MergeRange(D1:C1): %v", err)
+	}
+	cell, ok := tbl.Cell("C1")
+	if !ok {
+		t.Fatalf("Cell(C1) not found after MergeRange(D1:C1)")
+	}
+	rng, merged := cell.Merge()
+	if !merged || rng.EndCol != 3 {
+		t.Errorf("Cell(C1).Merge() = %+v, %v, want anchor merged through column D", rng, merged)
+	}
+}
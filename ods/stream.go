@@ -0,0 +1,266 @@
+package ods
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Stream reads content.xml incrementally with xml.Decoder.Token rather
+// than decoding the whole document at once, the way ParseContent does.
+// It parses automatic-styles fully up front - every cell needs it to
+// consolidate its style - then yields tables and rows one at a time via
+// NextTable and TableHeader.Rows, so spreadsheets too large to hold
+// entirely in memory can still be processed.
+type Stream struct {
+	closer       io.Closer
+	dec          *xml.Decoder
+	styles       []Style
+	numberStyles []NumberStyle
+
+	// ahead holds a token already read from dec but not yet acted on,
+	// e.g. the office:body start element found while looking for
+	// automatic-styles in a document that has none.
+	ahead xml.Token
+
+	// pendingRow is the table-row start element of the next row to
+	// decode, found while scanning the current table's children. nil
+	// once the table's closing tag has been reached.
+	pendingRow *xml.StartElement
+}
+
+// TableHeader is a table's name and column styles, discovered by
+// Stream.NextTable. Call Rows to stream the table's rows without
+// materializing them all at once.
+type TableHeader struct {
+	Name    string
+	Columns []TColumn
+
+	s *Stream
+}
+
+// autoStyles mirrors Doc's automatic-styles fields, scoped to decoding
+// just that one element while streaming.
+type autoStyles struct {
+	Style           []Style       `xml:"style"`
+	DateStyle       []NumberStyle `xml:"date-style"`
+	TimeStyle       []NumberStyle `xml:"time-style"`
+	NumberStyle     []NumberStyle `xml:"number-style"`
+	PercentageStyle []NumberStyle `xml:"percentage-style"`
+	CurrencyStyle   []NumberStyle `xml:"currency-style"`
+	BooleanStyle    []NumberStyle `xml:"boolean-style"`
+}
+
+// StreamContent opens content.xml and reads just far enough to parse
+// automatic-styles, leaving the rest of the document - the tables and
+// their rows - to be walked with NextTable/Rows.
+func (f *File) StreamContent() (*Stream, error) {
+	content, err := f.Open("content.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Stream{closer: content, dec: xml.NewDecoder(content)}
+	if err := s.readStyles(); err != nil {
+		content.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the underlying content.xml reader.
+func (s *Stream) Close() error {
+	return s.closer.Close()
+}
+
+// nextToken returns a previously stashed lookahead token if there is
+// one, otherwise reads the next token from dec.
+func (s *Stream) nextToken() (xml.Token, error) {
+	if s.ahead != nil {
+		t := s.ahead
+		s.ahead = nil
+		return t, nil
+	}
+	return s.dec.Token()
+}
+
+func (s *Stream) readStyles() error {
+	for {
+		tok, err := s.nextToken()
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "automatic-styles":
+			var as autoStyles
+			if err := s.dec.DecodeElement(&as, &start); err != nil {
+				return err
+			}
+			s.styles = as.Style
+			s.numberStyles = append(s.numberStyles, as.DateStyle...)
+			s.numberStyles = append(s.numberStyles, as.TimeStyle...)
+			s.numberStyles = append(s.numberStyles, as.NumberStyle...)
+			s.numberStyles = append(s.numberStyles, as.PercentageStyle...)
+			s.numberStyles = append(s.numberStyles, as.CurrencyStyle...)
+			s.numberStyles = append(s.numberStyles, as.BooleanStyle...)
+			return nil
+		case "body":
+			// No automatic-styles element in this document; stash the
+			// body start element for NextTable to pick up.
+			s.ahead = start
+			return nil
+		}
+	}
+}
+
+// NextTable advances to the next table:table element and returns its
+// name and column styles. It returns io.EOF once the document has no
+// further tables. If the previous TableHeader's Rows wasn't called, or
+// was stopped early, its remaining rows are skipped first.
+func (s *Stream) NextTable() (*TableHeader, error) {
+	if err := s.skipRemainingRows(); err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, err := s.nextToken()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "table" {
+			continue
+		}
+
+		h := &TableHeader{s: s}
+		for _, a := range start.Attr {
+			if a.Name.Local == "name" {
+				h.Name = a.Value
+			}
+		}
+		if err := h.readColumns(); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+}
+
+// skipRemainingRows discards any rows left over from a TableHeader
+// whose Rows either wasn't called or returned before reaching the
+// table's end, so the decoder is positioned to look for the next table.
+func (s *Stream) skipRemainingRows() error {
+	for s.pendingRow != nil {
+		if err := s.dec.Skip(); err != nil {
+			return err
+		}
+		if err := s.scanForNextRow(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readColumns collects the table's table-column children and stops at
+// its first table-row (stashed in s.pendingRow) or its closing tag.
+func (h *TableHeader) readColumns() error {
+	s := h.s
+	for {
+		tok, err := s.nextToken()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "table-column":
+				var col TColumn
+				if err := s.dec.DecodeElement(&col, &el); err != nil {
+					return err
+				}
+				h.Columns = append(h.Columns, col)
+			case "table-row":
+				s.pendingRow = &el
+				return nil
+			default:
+				if err := s.dec.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if el.Name.Local == "table" {
+				s.pendingRow = nil
+				return nil
+			}
+		}
+	}
+}
+
+// scanForNextRow walks table:table's remaining children looking for the
+// next table-row (stashed in s.pendingRow) or the table's closing tag
+// (s.pendingRow set to nil).
+func (s *Stream) scanForNextRow() error {
+	for {
+		tok, err := s.nextToken()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local == "table-row" {
+				s.pendingRow = &el
+				return nil
+			}
+			if err := s.dec.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if el.Name.Local == "table" {
+				s.pendingRow = nil
+				return nil
+			}
+		}
+	}
+}
+
+// Rows streams the table's rows in document order, invoking fn once per
+// logical row. A row using number-rows-repeated is expanded virtually:
+// fn is called RepeatedRows times against the one Row converted from it
+// rather than allocating RepeatedRows copies. Returning a non-nil error
+// from fn stops iteration early and that error is returned from Rows.
+//
+// Unlike Table.Rows, trailing empty rows are not trimmed - doing so
+// would require buffering rows until a non-empty one is seen, defeating
+// the point of streaming. Callers that care can check Row.IsEmpty.
+func (h *TableHeader) Rows(fn func(Row) error) error {
+	s := h.s
+
+	buf := getPlainTextBuf()
+	defer putPlainTextBuf(buf)
+
+	for s.pendingRow != nil {
+		start := *s.pendingRow
+		var tr TRow
+		if err := s.dec.DecodeElement(&tr, &start); err != nil {
+			return err
+		}
+
+		repeats := tr.RepeatedRows
+		if repeats == 0 {
+			repeats = 1
+		}
+		row := tr.Cells(buf, s.styles, GetRowStyleByName(tr.StyleName, s.styles), h.Columns, s.numberStyles...)
+		for i := 0; i < repeats; i++ {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+
+		if err := s.scanForNextRow(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package ods
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// TestStreamMatchesParseContent checks that streaming test.ods's first
+// table yields the same cell values, in the same order, as the
+// in-memory ParseContent/Table.Rows path.
+func TestStreamMatchesParseContent(t *testing.T) {
+	var doc Doc
+	pf, err := Open("./test.ods")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer pf.Close()
+	if err := pf.ParseContent(&doc); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if len(doc.Table) == 0 {
+		t.Fatal("test.ods has no tables")
+	}
+	want := doc.Table[0].Rows(doc.Style, doc.NumberStyles()...)
+
+	sf, err := Open("./test.ods")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer sf.Close()
+	s, err := sf.StreamContent()
+	if err != nil {
+		t.Fatalf("StreamContent: %v", err)
+	}
+	defer s.Close()
+
+	th, err := s.NextTable()
+	if err != nil {
+		t.Fatalf("NextTable: %v", err)
+	}
+	if th.Name != doc.Table[0].Name {
+		t.Errorf("NextTable name = %q, want %q", th.Name, doc.Table[0].Name)
+	}
+
+	var got []Row
+	if err := th.Rows(func(r Row) error {
+		got = append(got, r)
+		return nil
+	}); err != nil {
+		t.Fatalf("Rows: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for r := range want {
+		if len(got[r].Cell) != len(want[r].Cell) {
+			t.Errorf("row %d: got %d cells, want %d", r, len(got[r].Cell), len(want[r].Cell))
+			continue
+		}
+		for c := range want[r].Cell {
+			if got[r].Cell[c].Value != want[r].Cell[c].Value {
+				t.Errorf("row %d cell %d: got %q, want %q", r, c, got[r].Cell[c].Value, want[r].Cell[c].Value)
+			}
+		}
+	}
+}
+
+// TestStreamRepeatedRows checks that a row with number-rows-repeated is
+// replayed to the callback the right number of times, and that stopping
+// early by returning an error from the callback is honored.
+func TestStreamRepeatedRows(t *testing.T) {
+	f, err := Open("./test.ods")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer f.Close()
+	s, err := f.StreamContent()
+	if err != nil {
+		t.Fatalf("StreamContent: %v", err)
+	}
+	defer s.Close()
+
+	th, err := s.NextTable()
+	if err != nil {
+		t.Fatalf("NextTable: %v", err)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err = th.Rows(func(_ Row) error {
+		n++
+		if n == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Rows returned %v, want %v", err, stop)
+	}
+	if n != 3 {
+		t.Fatalf("callback ran %d times, want 3", n)
+	}
+}
+
+// TestStreamNextTableEOF checks that NextTable reports io.EOF once every
+// table has been consumed, and that a table whose rows were never read
+// doesn't wedge iteration.
+func TestStreamNextTableEOF(t *testing.T) {
+	f, err := Open("./test.ods")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer f.Close()
+	s, err := f.StreamContent()
+	if err != nil {
+		t.Fatalf("StreamContent: %v", err)
+	}
+	defer s.Close()
+
+	n := 0
+	for {
+		_, err := s.NextTable()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextTable: %v", err)
+		}
+		n++
+		if n > 10 {
+			t.Fatal("NextTable did not terminate")
+		}
+	}
+	if n == 0 {
+		t.Fatal("NextTable found no tables")
+	}
+}
@@ -0,0 +1,179 @@
+package ods
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// odsDurationLayout is the subset of ISO 8601 durations ODS emits for
+// office:time-value, e.g. "PT13H30M00S".
+func parseODSDuration(s string) (time.Time, bool) {
+	s = strings.TrimPrefix(s, "PT")
+	s = strings.TrimSuffix(s, "S")
+	var h, m int
+	var sec float64
+	if i := strings.IndexByte(s, 'H'); i >= 0 {
+		h, _ = strconv.Atoi(s[:i])
+		s = s[i+1:]
+	}
+	if i := strings.IndexByte(s, 'M'); i >= 0 {
+		m, _ = strconv.Atoi(s[:i])
+		s = s[i+1:]
+	}
+	if s != "" {
+		sec, _ = strconv.ParseFloat(s, 64)
+	}
+	return time.Date(0, 1, 1, h, m, int(sec), 0, time.UTC), true
+}
+
+func parseODSDate(s string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Float returns the cell's numeric value, for ValueType "float",
+// "percentage" or "currency". ok is false for any other ValueType, or if
+// the underlying office:value attribute could not be parsed.
+func (c *Cell) Float() (f float64, ok bool) {
+	switch c.ValueType {
+	case "float", "percentage", "currency":
+	default:
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(c.RawValue, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// Time returns the cell's date or time value, for ValueType "date" or
+// "time". A "time" cell is returned on the zero date, with only its
+// hour/minute/second fields meaningful.
+func (c *Cell) Time() (time.Time, bool) {
+	switch c.ValueType {
+	case "date":
+		return parseODSDate(c.DateValue)
+	case "time":
+		return parseODSDuration(c.TimeValue)
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Bool returns the cell's boolean value, for ValueType "boolean".
+func (c *Cell) Bool() (bool, bool) {
+	if c.ValueType != "boolean" {
+		return false, false
+	}
+	return c.BooleanValue == "true", true
+}
+
+// Currency returns the cell's numeric value together with its currency
+// code (e.g. "EUR"), for ValueType "currency".
+func (c *Cell) Currency() (float64, string, bool) {
+	if c.ValueType != "currency" {
+		return 0, "", false
+	}
+	f, ok := c.Float()
+	if !ok {
+		return 0, "", false
+	}
+	return f, c.CurrencyCode, true
+}
+
+// Formatted renders the cell's typed value using the number/date/time
+// format derived from its data style (see Format). Cells without an
+// associated data style, or whose typed value can't be parsed, fall back
+// to Value, the plain text already extracted from <text:p>. Conditional
+// formatting such as a style:map coloring negative numbers is not
+// applied; negative values always render with a plain minus sign.
+func (c *Cell) Formatted() string {
+	if c.Format == "" {
+		return c.Value
+	}
+	switch c.ValueType {
+	case "date", "time":
+		if t, ok := c.Time(); ok {
+			return t.Format(c.Format)
+		}
+	case "float", "percentage", "currency":
+		if f, ok := c.Float(); ok {
+			return formatNumber(f, c.Format, c.ValueType == "percentage")
+		}
+	case "boolean":
+		if b, ok := c.Bool(); ok {
+			if b {
+				return "TRUE"
+			}
+			return "FALSE"
+		}
+	}
+	return c.Value
+}
+
+// formatNumber renders f according to pattern, a NumberStyle.Format
+// string built of "0" (mandatory digit), "#,##" (thousands grouping) and
+// literal runes (currency symbols, separators). Percentage styles scale
+// the value by 100 and append "%".
+func formatNumber(f float64, pattern string, percent bool) string {
+	if percent {
+		f *= 100
+	}
+
+	decimals := 0
+	if i := strings.IndexByte(pattern, '.'); i >= 0 {
+		decimals = len(pattern) - i - 1
+	}
+	grouped := strings.Contains(pattern, "#,##")
+
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+	if grouped {
+		s = groupThousands(s)
+	}
+
+	// Splice the rendered number into the pattern in place of its
+	// digit run, keeping any literal prefix/suffix (currency symbols,
+	// percent signs, ...) intact.
+	start := strings.IndexAny(pattern, "0#")
+	end := strings.LastIndexAny(pattern, "0#")
+	if start < 0 {
+		return s
+	}
+	return pattern[:start] + s + pattern[end+1:]
+}
+
+// groupThousands inserts "," every three digits of the integer part of
+// a formatted decimal string.
+func groupThousands(s string) string {
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	intPart, frac, hasFrac := s, "", false
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac, hasFrac = s[:i], s[i:], true
+	}
+
+	var b strings.Builder
+	n := len(intPart)
+	for i, r := range intPart {
+		if i != 0 && (n-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	out := b.String()
+	if hasFrac {
+		out += frac
+	}
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package ods
+
+import "testing"
+
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		f       float64
+		pattern string
+		percent bool
+		want    string
+	}{
+		{1234.5, "#,##0.00", false, "1,234.50"},
+		{7, "0", false, "7"},
+		{0.5, "0%", true, "50%"},
+		{-12.3, "#,##0.0", false, "-12.3"},
+	}
+	for _, c := range cases {
+		got := formatNumber(c.f, c.pattern, c.percent)
+		if got != c.want {
+			t.Errorf("formatNumber(%v, %q, %v) = %q, want %q", c.f, c.pattern, c.percent, got, c.want)
+		}
+	}
+}
+
+func TestCellFormatted(t *testing.T) {
+	c := Cell{ValueType: "float", RawValue: "42.5", Format: "0.0"}
+	if got := c.Formatted(); got != "42.5" {
+		t.Errorf("Formatted() = %q, want %q", got, "42.5")
+	}
+
+	c = Cell{ValueType: "boolean", BooleanValue: "true", Format: "BOOL"}
+	if got := c.Formatted(); got != "TRUE" {
+		t.Errorf("Formatted() = %q, want %q", got, "TRUE")
+	}
+}
+
+func TestParseODSDuration(t *testing.T) {
+	tm, ok := parseODSDuration("PT13H30M00S")
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if tm.Hour() != 13 || tm.Minute() != 30 {
+		t.Errorf("got hour=%d minute=%d, want 13:30", tm.Hour(), tm.Minute())
+	}
+}